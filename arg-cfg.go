@@ -0,0 +1,134 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargs
+
+import (
+	"fmt"
+)
+
+// ConfigArgIsMisplaced is an error which indicates that a command argument
+// configuration is placed after an optional command argument
+// (.IsOptional = true) though it is required, or after a variadic command
+// argument (.IsVariadic = true).
+type ConfigArgIsMisplaced struct{ Arg string }
+
+func (e ConfigArgIsMisplaced) Error() string {
+	return fmt.Sprintf("ConfigArgIsMisplaced{Arg:%s}", e.Arg)
+}
+
+// MissingArg is an error which indicates that a required command argument is
+// not given in command line arguments.
+// Name is the name of the command argument configuration.
+type MissingArg struct{ Name string }
+
+func (e MissingArg) Error() string {
+	return fmt.Sprintf("MissingArg{Name:%s}", e.Name)
+}
+
+// ExtraArg is an error which indicates that more command arguments are given
+// than the command argument configurations accept.
+// Arg is the first command argument which is not accepted.
+type ExtraArg struct{ Arg string }
+
+func (e ExtraArg) Error() string {
+	return fmt.Sprintf("ExtraArg{Arg:%s}", e.Arg)
+}
+
+// ArgCfg is a structure that represents a command argument configuration.
+// A command argument configuration consists of fields: Name, IsOptional,
+// IsVariadic, and Desc.
+//
+// Name is the name of the command argument, which is used in errors and in a
+// help text.
+//
+// IsOptional is a flag which indicates that the command argument can be
+// omitted.
+// A required command argument cannot follow an optional command argument.
+//
+// IsVariadic is a flag which indicates that the command argument takes all the
+// remaining command arguments.
+// A variadic command argument requires one or more command arguments, or zero
+// or more command arguments if IsOptional is true.
+// A variadic command argument must be the last command argument.
+//
+// Desc is the field to set the description of the command argument.
+type ArgCfg struct {
+	Name       string
+	IsOptional bool
+	IsVariadic bool
+	Desc       string
+}
+
+func checkArgCfgs(argCfgs []ArgCfg) error {
+	hasOptional := false
+	for i, cfg := range argCfgs {
+		if i > 0 && argCfgs[i-1].IsVariadic {
+			return ConfigArgIsMisplaced{Arg: cfg.Name}
+		}
+		if cfg.IsOptional {
+			hasOptional = true
+		} else if hasOptional {
+			return ConfigArgIsMisplaced{Arg: cfg.Name}
+		}
+	}
+	return nil
+}
+
+// checkArgs is a function which checks the number of command arguments with
+// command argument configurations.
+// If argCfgs is nil, any number of command arguments are accepted.
+func checkArgs(argCfgs []ArgCfg, args []string) error {
+	if argCfgs == nil {
+		return nil
+	}
+
+	err := checkArgCfgs(argCfgs)
+	if err != nil {
+		return err
+	}
+
+	for i, cfg := range argCfgs {
+		if i >= len(args) {
+			if !cfg.IsOptional {
+				return MissingArg{Name: cfg.Name}
+			}
+			return nil
+		}
+		if cfg.IsVariadic {
+			return nil
+		}
+	}
+
+	if len(args) > len(argCfgs) {
+		return ExtraArg{Arg: args[len(argCfgs)]}
+	}
+	return nil
+}
+
+// makeArgTitle is a function which returns the display of a command argument
+// in a usage synopsis and in a help text, like <file>, [<file>], <file>...,
+// or [<file>...].
+func makeArgTitle(cfg ArgCfg) string {
+	title := "<" + cfg.Name + ">"
+	if cfg.IsVariadic {
+		title += "..."
+	}
+	if cfg.IsOptional {
+		title = "[" + title + "]"
+	}
+	return title
+}
+
+// MakeUsageArgs is a function which makes the texts of command arguments for
+// Help#AddUsage method from command argument configurations, like:
+//
+//	[]string{"<src>", "[<dest>...]"}
+func MakeUsageArgs(argCfgs []ArgCfg) []string {
+	args := make([]string, len(argCfgs))
+	for i, cfg := range argCfgs {
+		args[i] = makeArgTitle(cfg)
+	}
+	return args
+}
@@ -0,0 +1,182 @@
+package cliargs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sttk/cliargs"
+)
+
+func TestParseWith_argCfgs(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+	}
+	argCfgs := []cliargs.ArgCfg{
+		cliargs.ArgCfg{Name: "src"},
+		cliargs.ArgCfg{Name: "dest", IsOptional: true},
+	}
+
+	osArgs := []string{"app", "a", "-v", "b"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs,
+		cliargs.WithArgCfgs(argCfgs...))
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("verbose"))
+	assert.Equal(t, cmd.Args(), []string{"a", "b"})
+
+	osArgs = []string{"app", "a"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs,
+		cliargs.WithArgCfgs(argCfgs...))
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.Args(), []string{"a"})
+
+	osArgs = []string{"app", "-v"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs,
+		cliargs.WithArgCfgs(argCfgs...))
+	assert.Equal(t, err.Error(), "MissingArg{Name:src}")
+	switch e := err.(type) {
+	case cliargs.MissingArg:
+		assert.Equal(t, e.Name, "src")
+	default:
+		assert.Fail(t, err.Error())
+	}
+	assert.Equal(t, cmd.Args(), []string{})
+
+	osArgs = []string{"app", "a", "b", "c", "d"}
+	_, err = cliargs.ParseWith(osArgs, optCfgs,
+		cliargs.WithArgCfgs(argCfgs...))
+	assert.Equal(t, err.Error(), "ExtraArg{Arg:c}")
+	switch e := err.(type) {
+	case cliargs.ExtraArg:
+		assert.Equal(t, e.Arg, "c")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseWith_argCfgs_variadic(t *testing.T) {
+	argCfgs := []cliargs.ArgCfg{
+		cliargs.ArgCfg{Name: "src"},
+		cliargs.ArgCfg{Name: "dest"},
+		cliargs.ArgCfg{Name: "more", IsOptional: true, IsVariadic: true},
+	}
+
+	osArgs := []string{"cp", "a", "b", "c", "d"}
+	cmd, err := cliargs.ParseWith(osArgs, nil,
+		cliargs.WithArgCfgs(argCfgs...))
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.Args(), []string{"a", "b", "c", "d"})
+
+	osArgs = []string{"cp", "a", "b"}
+	_, err = cliargs.ParseWith(osArgs, nil,
+		cliargs.WithArgCfgs(argCfgs...))
+	assert.Nil(t, err)
+
+	osArgs = []string{"cp", "a"}
+	_, err = cliargs.ParseWith(osArgs, nil,
+		cliargs.WithArgCfgs(argCfgs...))
+	assert.Equal(t, err.Error(), "MissingArg{Name:dest}")
+
+	argCfgs = []cliargs.ArgCfg{
+		cliargs.ArgCfg{Name: "file", IsVariadic: true},
+	}
+
+	osArgs = []string{"cat"}
+	_, err = cliargs.ParseWith(osArgs, nil, cliargs.WithArgCfgs(argCfgs...))
+	assert.Equal(t, err.Error(), "MissingArg{Name:file}")
+}
+
+func TestParseWith_argCfgs_noArg(t *testing.T) {
+	osArgs := []string{"app"}
+	_, err := cliargs.ParseWith(osArgs, nil, cliargs.WithArgCfgs())
+	assert.Nil(t, err)
+
+	osArgs = []string{"app", "a"}
+	_, err = cliargs.ParseWith(osArgs, nil, cliargs.WithArgCfgs())
+	assert.Equal(t, err.Error(), "ExtraArg{Arg:a}")
+
+	_, err = cliargs.ParseWith(osArgs, nil)
+	assert.Nil(t, err)
+}
+
+func TestParseWith_argCfgs_misplaced(t *testing.T) {
+	argCfgs := []cliargs.ArgCfg{
+		cliargs.ArgCfg{Name: "a", IsOptional: true},
+		cliargs.ArgCfg{Name: "b"},
+	}
+	_, err := cliargs.ParseWith([]string{"app", "x", "y"}, nil,
+		cliargs.WithArgCfgs(argCfgs...))
+	assert.Equal(t, err.Error(), "ConfigArgIsMisplaced{Arg:b}")
+
+	argCfgs = []cliargs.ArgCfg{
+		cliargs.ArgCfg{Name: "a", IsVariadic: true},
+		cliargs.ArgCfg{Name: "b", IsOptional: true},
+	}
+	_, err = cliargs.ParseWith([]string{"app", "x", "y"}, nil,
+		cliargs.WithArgCfgs(argCfgs...))
+	switch e := err.(type) {
+	case cliargs.ConfigArgIsMisplaced:
+		assert.Equal(t, e.Arg, "b")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseFor_argCfgs(t *testing.T) {
+	type MyOptions struct {
+		Force bool `optcfg:"force,f"`
+	}
+
+	argCfgs := []cliargs.ArgCfg{
+		cliargs.ArgCfg{Name: "src"},
+		cliargs.ArgCfg{Name: "dest"},
+		cliargs.ArgCfg{Name: "more", IsOptional: true, IsVariadic: true},
+	}
+
+	options := MyOptions{}
+	osArgs := []string{"cp", "-f", "a"}
+	_, _, err := cliargs.ParseFor(osArgs, &options,
+		cliargs.WithArgCfgs(argCfgs...))
+	assert.Equal(t, err.Error(), "MissingArg{Name:dest}")
+}
+
+func TestParseCmd_argCfgs(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name:    "app",
+		ArgCfgs: []cliargs.ArgCfg{},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name: "cp",
+				ArgCfgs: []cliargs.ArgCfg{
+					cliargs.ArgCfg{Name: "src"},
+					cliargs.ArgCfg{Name: "dest"},
+					cliargs.ArgCfg{Name: "more", IsOptional: true, IsVariadic: true},
+				},
+			},
+			cliargs.CmdCfg{Name: "ls"},
+		},
+	}
+
+	cmd, err := cliargs.ParseCmd([]string{"app", "cp", "a", "b"}, cmdCfg)
+	assert.Nil(t, err)
+	subCmd, _ := cmd.SubCmd()
+	assert.Equal(t, subCmd.Args(), []string{"a", "b"})
+
+	_, err = cliargs.ParseCmd([]string{"app", "cp", "a"}, cmdCfg)
+	assert.Equal(t, err.Error(), "MissingArg{Name:dest}")
+
+	_, err = cliargs.ParseCmd([]string{"app", "ls", "a", "b"}, cmdCfg,
+		cliargs.WithArgCfgs())
+	assert.Nil(t, err)
+}
+
+func TestMakeUsageArgs(t *testing.T) {
+	argCfgs := []cliargs.ArgCfg{
+		cliargs.ArgCfg{Name: "src"},
+		cliargs.ArgCfg{Name: "dest"},
+		cliargs.ArgCfg{Name: "more", IsOptional: true, IsVariadic: true},
+	}
+	assert.Equal(t, cliargs.MakeUsageArgs(argCfgs),
+		[]string{"<src>", "<dest>", "[<more>...]"})
+	assert.Equal(t, cliargs.MakeUsageArgs(nil), []string{})
+}
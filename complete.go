@@ -0,0 +1,378 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargs
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const completeSubCmd = "__complete"
+
+// RunCompletion is a function which runs the hidden "__complete" sub command
+// that is called back from shell completion scripts generated by
+// MakeBashCompletion, MakeZshCompletion, and MakeFishCompletion.
+//
+// If the second element of osArgs is "__complete", this function prints the
+// candidates of the last element of the remaining command line arguments to
+// standard output, one per line, and returns true.
+// Otherwise, this function does nothing and returns false.
+// This function should be called before parsing command line arguments, as
+// follows:
+//
+//	if cliargs.RunCompletion(os.Args, cmdCfg) {
+//	    return
+//	}
+func RunCompletion(
+	osArgs []string, cmdCfg CmdCfg, parseOpts ...ParseOpt,
+) bool {
+	if len(osArgs) < 2 || osArgs[1] != completeSubCmd {
+		return false
+	}
+
+	for _, c := range CompleteArgs(osArgs, cmdCfg, parseOpts...) {
+		fmt.Println(c)
+	}
+	return true
+}
+
+// CompleteArgs is a function which returns the candidates of the last element
+// of command line arguments, which is being input.
+// The first element of osArgs is the command path, and the second element can
+// be "__complete", which is ignored.
+//
+// The command line arguments before the last element are parsed with the
+// command configuration as far as possible.
+// If the last element is an option argument, the candidates are returned by
+// OnComplete of the option configuration, or are its Choices, or are according
+// to its CompHint.
+// Even when the last element is like --option=prefix, the candidates are
+// option arguments without --option=.
+// If the last element starts with "-", the candidates are the names and
+// aliases of the options, and the negated names of the negatable options, like
+// --no-color.
+// Otherwise, the candidates are the names and aliases of the sub commands, or
+// are returned by OnComplete of the command configuration if the command has
+// no sub command.
+func CompleteArgs(
+	osArgs []string, cmdCfg CmdCfg, parseOpts ...ParseOpt,
+) []string {
+	var cmdName string
+	if len(osArgs) > 0 {
+		cmdName = path.Base(osArgs[0])
+	}
+
+	var words []string
+	if len(osArgs) > 1 {
+		words = osArgs[1:]
+	}
+	if len(words) > 0 && words[0] == completeSubCmd {
+		words = words[1:]
+	}
+
+	cur := ""
+	if len(words) > 0 {
+		cur = words[len(words)-1]
+		words = words[0 : len(words)-1]
+	}
+
+	pc := newParseCfg(parseOpts)
+
+	if pc.responseFiles {
+		var err error
+		words, err = expandResponseFiles(words)
+		if err != nil {
+			return nil
+		}
+	}
+
+	return completeCmd(words, cur, cmdCfg, cmdName, nil, nil, pc)
+}
+
+func completeCmd(
+	words []string,
+	cur string,
+	cmdCfg CmdCfg,
+	cmdName string,
+	globals []globalOpts,
+	parent *Cmd,
+	pc parseCfg,
+) []string {
+	// An option configuration of "*" is added so as to parse as far as
+	// possible even if unconfigured options are included.
+	optCfgs := make([]OptCfg, len(cmdCfg.OptCfgs), len(cmdCfg.OptCfgs)+1)
+	copy(optCfgs, cmdCfg.OptCfgs)
+	optCfgs = append(optCfgs, OptCfg{Name: anyOption})
+
+	hasSubCmds := len(cmdCfg.SubCmds) > 0
+	pc.untilFirstArg = hasSubCmds
+
+	args, opts, iArg, err := parseWith(words, optCfgs, globals, pc)
+	cmd := &Cmd{Name: cmdName, args: args, opts: opts, parent: parent}
+	cmd.globals = globalNames(cmdCfg.OptCfgs)
+	cmd.arities = arityMap(cmdCfg.OptCfgs)
+
+	if err != nil {
+		// The option word is the last word if the option needs an option
+		// argument, or is before the given option arguments if the option needs
+		// more option arguments. The first option argument can be attached to
+		// the option word with "=".
+		var option, w string
+		switch e := err.(type) {
+		case OptionNeedsArg:
+			option = e.Option
+			if len(words) > 0 {
+				w = words[len(words)-1]
+			}
+		case OptionNeedsMoreArgs:
+			option = e.Option
+			if i := len(words) - e.Given; i >= 0 && i < len(words) &&
+				strings.HasPrefix(words[i], "-") &&
+				strings.ContainsRune(words[i], '=') {
+				w = words[i][:strings.IndexRune(words[i], '=')]
+			} else if i > 0 {
+				w = words[i-1]
+			}
+		default:
+			return nil
+		}
+		if len(w) == 0 || strings.ContainsRune(w, '=') {
+			return nil
+		}
+		var name string
+		isLong := strings.HasPrefix(w, "--")
+		if isLong {
+			name = w[2:]
+		} else if strings.HasPrefix(w, "-") {
+			name = w[len(w)-1:]
+		}
+		cfg, exists := findOptCfg(
+			name, cmdCfg.OptCfgs, globals, isLong && pc.longOptAbbrev)
+		if !exists || cfg.Name != option {
+			return nil
+		}
+		return completeOptArg(cfg, *cmd, cur)
+	}
+
+	if iArg >= 0 {
+		subCfg, exists := findSubCmdCfg(cmdCfg.SubCmds, words[iArg])
+		if !exists {
+			return nil
+		}
+
+		subGlobals := appendGlobalOpts(globals, cmdCfg.OptCfgs, cmd)
+
+		return completeCmd(
+			words[iArg+1:], cur, subCfg, subCfg.Name, subGlobals, cmd, pc)
+	}
+
+	// Options are not completed after a command argument if the parsing stops
+	// at the first command argument.
+	isNonOpt := pc.stopAtFirstArg && len(args) > 0
+	for _, w := range words {
+		if w == "--" {
+			isNonOpt = true
+			break
+		}
+	}
+
+	if !isNonOpt && strings.HasPrefix(cur, "-") {
+		if i := strings.IndexRune(cur, '='); i > 0 {
+			var name string
+			isLong := strings.HasPrefix(cur, "--")
+			if isLong {
+				name = cur[2:i]
+			} else {
+				name = cur[i-1 : i]
+			}
+			cfg, exists := findOptCfg(
+				name, cmdCfg.OptCfgs, globals, isLong && pc.longOptAbbrev)
+			if !exists || !cfg.HasArg {
+				return nil
+			}
+			return completeOptArg(cfg, *cmd, cur[i+1:])
+		}
+
+		cands := make([]string, 0)
+		for _, g := range globals {
+			for _, cfg := range g.optCfgs {
+				if cfg.IsGlobal && cfg.Name != anyOption &&
+					!hasOptName(cmdCfg.OptCfgs, cfg.Name) {
+					cands = append(cands, optNames(cfg)...)
+					cands = append(cands, negOptNames(cfg)...)
+				}
+			}
+		}
+		for _, cfg := range cmdCfg.OptCfgs {
+			if cfg.Name != anyOption {
+				cands = append(cands, optNames(cfg)...)
+				cands = append(cands, negOptNames(cfg)...)
+			}
+		}
+		return filterCands(cands, cur)
+	}
+
+	if hasSubCmds && !isNonOpt {
+		cands := make([]string, 0, len(cmdCfg.SubCmds))
+		for _, sub := range cmdCfg.SubCmds {
+			cands = append(cands, subCmdNames(sub)...)
+		}
+		return filterCands(cands, cur)
+	}
+
+	if cmdCfg.OnComplete != nil {
+		return filterCands((*cmdCfg.OnComplete)(*cmd, cur), cur)
+	}
+	return nil
+}
+
+func findOptCfg(
+	name string, optCfgs []OptCfg, globals []globalOpts, abbrev bool,
+) (OptCfg, bool) {
+	for _, cfg := range optCfgs {
+		if cfg.Name == name {
+			return cfg, true
+		}
+		for _, a := range cfg.Aliases {
+			if a == name {
+				return cfg, true
+			}
+		}
+	}
+	for i := len(globals) - 1; i >= 0; i-- {
+		for _, cfg := range globals[i].optCfgs {
+			if !cfg.IsGlobal {
+				continue
+			}
+			if cfg.Name == name {
+				return cfg, true
+			}
+			for _, a := range cfg.Aliases {
+				if a == name {
+					return cfg, true
+				}
+			}
+		}
+	}
+
+	if abbrev {
+		cfgs := make([]OptCfg, 0, len(optCfgs))
+		for _, g := range globals {
+			for _, cfg := range g.optCfgs {
+				if cfg.IsGlobal && !hasOptName(optCfgs, cfg.Name) {
+					cfgs = append(cfgs, cfg)
+				}
+			}
+		}
+		cfgs = append(cfgs, optCfgs...)
+
+		resolved, err := resolveLongOptAbbrev(name, cfgs)
+		if err == nil && resolved != name {
+			return findOptCfg(resolved, optCfgs, globals, false)
+		}
+	}
+	return OptCfg{}, false
+}
+
+func completeOptArg(cfg OptCfg, cmd Cmd, prefix string) []string {
+	var fn *func(Cmd, string) []string
+	if cfg.OnComplete != nil {
+		fn = cfg.OnComplete
+	} else if len(cfg.Choices) > 0 {
+		fn = CompleteChoices(cfg.Choices...)
+	} else {
+		switch cfg.CompHint {
+		case COMP_FILE:
+			fn = CompleteFiles()
+		case COMP_DIR:
+			fn = CompleteDirs()
+		default:
+			return nil
+		}
+	}
+	return filterCands((*fn)(cmd, prefix), prefix)
+}
+
+func filterCands(cands []string, prefix string) []string {
+	filtered := make([]string, 0, len(cands))
+	for _, c := range cands {
+		if strings.HasPrefix(c, prefix) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// CompleteFiles is a function which creates an event handler for OnComplete
+// field of OptCfg or CmdCfg, which returns the paths of files and directories
+// starting with the prefix.
+// The paths of directories end with a path separator.
+func CompleteFiles() *func(Cmd, string) []string {
+	fn := func(_ Cmd, prefix string) []string {
+		return completePaths(prefix, false)
+	}
+	return &fn
+}
+
+// CompleteDirs is a function which creates an event handler for OnComplete
+// field of OptCfg or CmdCfg, which returns the paths of directories starting
+// with the prefix.
+// The paths of directories end with a path separator.
+func CompleteDirs() *func(Cmd, string) []string {
+	fn := func(_ Cmd, prefix string) []string {
+		return completePaths(prefix, true)
+	}
+	return &fn
+}
+
+// CompleteChoices is a function which creates an event handler for
+// OnComplete field of OptCfg or CmdCfg, which returns the specified choices
+// starting with the prefix.
+func CompleteChoices(choices ...string) *func(Cmd, string) []string {
+	fn := func(_ Cmd, prefix string) []string {
+		return filterCands(choices, prefix)
+	}
+	return &fn
+}
+
+func completePaths(prefix string, dirOnly bool) []string {
+	dir, base := filepath.Split(prefix)
+
+	readDir := dir
+	if len(readDir) == 0 {
+		readDir = "."
+	}
+
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		return nil
+	}
+
+	cands := make([]string, 0, len(entries))
+	for _, ent := range entries {
+		name := ent.Name()
+		if !strings.HasPrefix(name, base) {
+			continue
+		}
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(base, ".") {
+			continue
+		}
+		isDir := ent.IsDir()
+		if !isDir && ent.Type()&os.ModeSymlink != 0 {
+			if fi, e := os.Stat(filepath.Join(readDir, name)); e == nil {
+				isDir = fi.IsDir()
+			}
+		}
+		if isDir {
+			cands = append(cands, dir+name+string(filepath.Separator))
+		} else if !dirOnly {
+			cands = append(cands, dir+name)
+		}
+	}
+	return cands
+}
@@ -0,0 +1,319 @@
+package cliargs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sttk/cliargs"
+)
+
+func TestCompleteArgs_subCmdNames(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{Name: "build", Aliases: []string{"b"}},
+			cliargs.CmdCfg{Name: "clean"},
+		},
+	}
+
+	cands := cliargs.CompleteArgs([]string{"app", "__complete", ""}, cmdCfg)
+	assert.Equal(t, cands, []string{"build", "b", "clean"})
+
+	cands = cliargs.CompleteArgs([]string{"app", "__complete", "-v", "c"}, cmdCfg)
+	assert.Equal(t, cands, []string{"clean"})
+
+	cands = cliargs.CompleteArgs([]string{"app", "__complete", "--", "c"}, cmdCfg)
+	assert.Equal(t, len(cands), 0)
+}
+
+func TestCompleteArgs_optNames(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:       "color",
+				HasArg:     true,
+				IsGlobal:   true,
+				OnComplete: cliargs.CompleteChoices("always", "auto", "never"),
+			},
+			cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name:    "build",
+				Aliases: []string{"b"},
+				OptCfgs: []cliargs.OptCfg{
+					cliargs.OptCfg{Name: "target", Aliases: []string{"t"}, HasArg: true},
+				},
+			},
+		},
+	}
+
+	cands := cliargs.CompleteArgs([]string{"app", "__complete", "-"}, cmdCfg)
+	assert.Equal(t, cands, []string{"--color", "--verbose", "-v"})
+
+	cands = cliargs.CompleteArgs([]string{"app", "__complete", "build", "--"}, cmdCfg)
+	assert.Equal(t, cands, []string{"--color", "--target"})
+}
+
+func TestCompleteArgs_optArg(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:       "color",
+				HasArg:     true,
+				IsGlobal:   true,
+				OnComplete: cliargs.CompleteChoices("always", "auto", "never"),
+			},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name:    "build",
+				Aliases: []string{"b"},
+				OptCfgs: []cliargs.OptCfg{
+					cliargs.OptCfg{Name: "target", Aliases: []string{"t"}, HasArg: true},
+				},
+			},
+		},
+	}
+
+	cands := cliargs.CompleteArgs([]string{"app", "__complete", "--color", "a"}, cmdCfg)
+	assert.Equal(t, cands, []string{"always", "auto"})
+
+	cands = cliargs.CompleteArgs([]string{"app", "__complete", "--color=n"}, cmdCfg)
+	assert.Equal(t, cands, []string{"never"})
+
+	cands = cliargs.CompleteArgs([]string{"app", "__complete", "b", "--color", ""}, cmdCfg)
+	assert.Equal(t, cands, []string{"always", "auto", "never"})
+
+	cands = cliargs.CompleteArgs([]string{"app", "__complete", "b", "-t", ""}, cmdCfg)
+	assert.Nil(t, cands)
+}
+
+func TestCompleteArgs_cmdArg(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name:    "build",
+				Aliases: []string{"b"},
+				OptCfgs: []cliargs.OptCfg{
+					cliargs.OptCfg{Name: "target", Aliases: []string{"t"}, HasArg: true},
+				},
+				OnComplete: cliargs.CompleteChoices("main", "test"),
+			},
+			cliargs.CmdCfg{Name: "clean"},
+		},
+	}
+
+	cands := cliargs.CompleteArgs([]string{"app", "__complete", "build", "-t", "x", ""}, cmdCfg)
+	assert.Equal(t, cands, []string{"main", "test"})
+
+	cands = cliargs.CompleteArgs([]string{"app", "__complete", "build", "--", "-"}, cmdCfg)
+	assert.Equal(t, cands, []string{})
+
+	cands = cliargs.CompleteArgs([]string{"app", "__complete", "clean", ""}, cmdCfg)
+	assert.Nil(t, cands)
+
+	cands = cliargs.CompleteArgs([]string{"app", "__complete", "foo", ""}, cmdCfg)
+	assert.Nil(t, cands)
+}
+
+func TestCompleteArgs_withoutCompleteSubCmd(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name:       "build",
+				Aliases:    []string{"b"},
+				OnComplete: cliargs.CompleteChoices("main", "test"),
+			},
+		},
+	}
+
+	cands := cliargs.CompleteArgs([]string{"app", "build", "t"}, cmdCfg)
+	assert.Equal(t, cands, []string{"test"})
+}
+
+func TestCompleteArgs_compHint(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "sub"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "file.txt"), nil, 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, ".hidden"), nil, 0644))
+
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "file", HasArg: true, CompHint: cliargs.COMP_FILE},
+			cliargs.OptCfg{Name: "dir", HasArg: true, CompHint: cliargs.COMP_DIR},
+		},
+	}
+
+	prefix := dir + string(filepath.Separator)
+	sep := string(filepath.Separator)
+
+	cands := cliargs.CompleteArgs([]string{"app", "--file", prefix}, cmdCfg)
+	assert.Equal(t, cands, []string{prefix + "file.txt", prefix + "sub" + sep})
+
+	cands = cliargs.CompleteArgs([]string{"app", "--file", prefix + "."}, cmdCfg)
+	assert.Equal(t, cands, []string{prefix + ".hidden"})
+
+	cands = cliargs.CompleteArgs([]string{"app", "--dir=" + prefix}, cmdCfg)
+	assert.Equal(t, cands, []string{prefix + "sub" + sep})
+}
+
+func TestRunCompletion_notCompleteSubCmd(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{Name: "build", Aliases: []string{"b"}},
+		},
+	}
+
+	assert.False(t, cliargs.RunCompletion([]string{"app", "build"}, cmdCfg))
+	assert.False(t, cliargs.RunCompletion([]string{"app"}, cmdCfg))
+}
+
+func TestCompleteArgs_longOptAbbrev(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:       "color",
+				HasArg:     true,
+				IsGlobal:   true,
+				OnComplete: cliargs.CompleteChoices("always", "auto", "never"),
+			},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{Name: "build", Aliases: []string{"b"}},
+		},
+	}
+
+	cands := cliargs.CompleteArgs([]string{"app", "--col", "a"}, cmdCfg,
+		cliargs.WithLongOptAbbrev())
+	assert.Equal(t, cands, []string{"always", "auto"})
+
+	cands = cliargs.CompleteArgs([]string{"app", "b", "--col=n"}, cmdCfg,
+		cliargs.WithLongOptAbbrev())
+	assert.Equal(t, cands, []string{"never"})
+
+	cands = cliargs.CompleteArgs([]string{"app", "--col", "a"}, cmdCfg)
+	assert.Equal(t, cands, []string{})
+}
+
+func TestCompleteArgs_stopAtFirstArg(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:       "color",
+				HasArg:     true,
+				IsGlobal:   true,
+				OnComplete: cliargs.CompleteChoices("always", "auto", "never"),
+			},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name:    "build",
+				Aliases: []string{"b"},
+				OptCfgs: []cliargs.OptCfg{
+					cliargs.OptCfg{Name: "target", Aliases: []string{"t"}, HasArg: true},
+				},
+				OnComplete: cliargs.CompleteChoices("main", "test"),
+			},
+		},
+	}
+
+	cands := cliargs.CompleteArgs([]string{"app", "build", "--"}, cmdCfg,
+		cliargs.WithStopAtFirstArg())
+	assert.Equal(t, cands, []string{"--color", "--target"})
+
+	cands = cliargs.CompleteArgs([]string{"app", "build", "x", "-"}, cmdCfg,
+		cliargs.WithStopAtFirstArg())
+	assert.Equal(t, cands, []string{})
+
+	cands = cliargs.CompleteArgs([]string{"app", "build", "x", "-"}, cmdCfg)
+	assert.Equal(t, cands, []string{"--color", "--target", "-t"})
+}
+
+func TestCompleteArgs_choices(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:    "format",
+				HasArg:  true,
+				Choices: []string{"json", "yaml", "table"},
+			},
+		},
+	}
+
+	cands := cliargs.CompleteArgs([]string{"app", "--format", ""}, cmdCfg)
+	assert.Equal(t, cands, []string{"json", "yaml", "table"})
+
+	cands = cliargs.CompleteArgs([]string{"app", "--format=t"}, cmdCfg)
+	assert.Equal(t, cands, []string{"table"})
+}
+
+func TestCompleteArgs_negatableOpt(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:        "color",
+				Aliases:     []string{"c", "colour"},
+				IsNegatable: true,
+			},
+			cliargs.OptCfg{Name: "verbose"},
+		},
+	}
+
+	cands := cliargs.CompleteArgs([]string{"app", "__complete", "--no"}, cmdCfg)
+	assert.Equal(t, cands, []string{"--no-color", "--no-colour"})
+
+	cands = cliargs.CompleteArgs([]string{"app", "__complete", "--"}, cmdCfg)
+	assert.Equal(t, cands,
+		[]string{"--color", "--colour", "--no-color", "--no-colour", "--verbose"})
+}
+
+func TestCompleteArgs_arity(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:    "point",
+				Aliases: []string{"p"},
+				HasArg:  true,
+				Arity:   3,
+				Choices: []string{"10", "20"},
+			},
+		},
+		OnComplete: cliargs.CompleteChoices("main", "test"),
+	}
+
+	cands := cliargs.CompleteArgs([]string{"app", "--point", ""}, cmdCfg)
+	assert.Equal(t, cands, []string{"10", "20"})
+
+	cands = cliargs.CompleteArgs([]string{"app", "--point", "10", "2"}, cmdCfg)
+	assert.Equal(t, cands, []string{"20"})
+
+	cands = cliargs.CompleteArgs(
+		[]string{"app", "-p", "10", "20", ""}, cmdCfg)
+	assert.Equal(t, cands, []string{"10", "20"})
+
+	cands = cliargs.CompleteArgs(
+		[]string{"app", "--point=10", "20", ""}, cmdCfg)
+	assert.Equal(t, cands, []string{"10", "20"})
+
+	cands = cliargs.CompleteArgs(
+		[]string{"app", "--point", "10", "20", "10", ""}, cmdCfg)
+	assert.Equal(t, cands, []string{"main", "test"})
+}
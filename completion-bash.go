@@ -0,0 +1,274 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MakeBashCompletion is a function which generates a bash completion script
+// from a command configuration.
+// The Name of the command configuration is used as the command name which is
+// completed, so it is required.
+// If you have only an OptCfg array, wrap it with a CmdCfg like
+// CmdCfg{Name: "app", OptCfgs: optCfgs}.
+//
+// The generated script is self-contained, so it works without the
+// bash-completion package.
+// It completes long and short options of the command, and the names and
+// aliases of the sub commands if they are configured.
+// The negated names of a negatable option, like --no-color, are also
+// completed.
+// An option which takes no option argument does not consume the next
+// argument, an option of which Arity is greater than one consumes as many
+// arguments as its Arity, and no candidate is offered as the argument of an option which
+// takes an option argument unless its Choices or CompHint is specified.
+// Command arguments of a command without sub commands are completed as file
+// paths.
+//
+// If OnComplete of an option configuration or a command configuration is
+// specified, the script calls back the hidden "__complete" sub command of the
+// command to obtain candidates. (See RunCompletion function.)
+func MakeBashCompletion(cmdCfg CmdCfg) string {
+	nodes := collectCompNodes(cmdCfg)
+	fn := "_" + shellIdent(cmdCfg.Name)
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# bash completion for %s\n", cmdCfg.Name)
+	sb.WriteString("# This script is generated by github.com/sttk/cliargs.\n\n")
+
+	fmt.Fprintf(&sb, "%s_comp_subcmd() {\n", fn)
+	sb.WriteString("    case \"$1 $2\" in\n")
+	for _, node := range nodes {
+		for _, sub := range node.cmdCfg.SubCmds {
+			pats := make([]string, 0, len(sub.Aliases)+1)
+			for _, name := range subCmdNames(sub) {
+				pats = append(pats, bashQuote(node.path+" "+name))
+			}
+			fmt.Fprintf(&sb, "    %s) echo %s ;;\n",
+				strings.Join(pats, "|"), bashQuote(node.path+" "+sub.Name))
+		}
+	}
+	sb.WriteString("    esac\n")
+	sb.WriteString("}\n\n")
+
+	fmt.Fprintf(&sb, "%s_comp_words() {\n", fn)
+	sb.WriteString("    case \"$1\" in\n")
+	for _, node := range nodes {
+		words := make([]string, 0, len(node.optCfgs))
+		for _, cfg := range node.optCfgs {
+			words = append(words, optNames(cfg)...)
+			words = append(words, negOptNames(cfg)...)
+		}
+		fmt.Fprintf(&sb, "    %s) echo %s ;;\n",
+			bashQuote(node.path), bashQuote(strings.Join(words, " ")))
+	}
+	sb.WriteString("    esac\n")
+	sb.WriteString("}\n\n")
+
+	fmt.Fprintf(&sb, "%s_comp_subcmds() {\n", fn)
+	sb.WriteString("    case \"$1\" in\n")
+	for _, node := range nodes {
+		if len(node.cmdCfg.SubCmds) == 0 {
+			continue
+		}
+		words := make([]string, 0, len(node.cmdCfg.SubCmds))
+		for _, sub := range node.cmdCfg.SubCmds {
+			words = append(words, subCmdNames(sub)...)
+		}
+		fmt.Fprintf(&sb, "    %s) echo %s ;;\n",
+			bashQuote(node.path), bashQuote(strings.Join(words, " ")))
+	}
+	sb.WriteString("    esac\n")
+	sb.WriteString("}\n\n")
+
+	fmt.Fprintf(&sb, "%s_comp_hint() {\n", fn)
+	sb.WriteString("    case \"$1 $2\" in\n")
+	for _, node := range nodes {
+		for _, cfg := range node.optCfgs {
+			if !cfg.HasArg {
+				continue
+			}
+			// An optional option argument is completed only after "=", so the
+			// option is matched with "=" not to take the next word.
+			pats := make([]string, 0, len(cfg.Aliases)+1)
+			for _, name := range optNames(cfg) {
+				if cfg.IsArgOptional {
+					name += "="
+				}
+				pats = append(pats, bashQuote(node.path+" "+name))
+			}
+			fmt.Fprintf(&sb, "    %s) echo %s ;;\n",
+				strings.Join(pats, "|"), bashCompHint(cfg))
+		}
+	}
+	sb.WriteString("    *) return 1 ;;\n")
+	sb.WriteString("    esac\n")
+	sb.WriteString("}\n\n")
+
+	fmt.Fprintf(&sb, "%s_comp_arity() {\n", fn)
+	sb.WriteString("    case \"$1 $2\" in\n")
+	for _, node := range nodes {
+		for _, cfg := range node.optCfgs {
+			if arityOf(cfg) <= 1 {
+				continue
+			}
+			pats := make([]string, 0, len(cfg.Aliases)+1)
+			for _, name := range optNames(cfg) {
+				pats = append(pats, bashQuote(node.path+" "+name))
+			}
+			fmt.Fprintf(&sb, "    %s) echo %d ;;\n",
+				strings.Join(pats, "|"), arityOf(cfg))
+		}
+	}
+	sb.WriteString("    *) echo 1 ;;\n")
+	sb.WriteString("    esac\n")
+	sb.WriteString("}\n\n")
+
+	fmt.Fprintf(&sb, "%s_comp_args() {\n", fn)
+	sb.WriteString("    case \"$1\" in\n")
+	for _, node := range nodes {
+		if len(node.cmdCfg.SubCmds) == 0 && node.cmdCfg.OnComplete != nil {
+			fmt.Fprintf(&sb, "    %s) echo call ;;\n", bashQuote(node.path))
+		}
+	}
+	sb.WriteString("    *) echo file ;;\n")
+	sb.WriteString("    esac\n")
+	sb.WriteString("}\n\n")
+
+	fmt.Fprintf(&sb, `%[1]s_comp() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" cmd=%[2]s next w i
+    local nonopt=0 lastopt="" argopt="" argn=0 joined=0 word
+    local -a args=()
+    COMPREPLY=()
+
+    for (( i=1; i<COMP_CWORD; i++ )); do
+        w="${COMP_WORDS[i]}"
+        if [[ "$w" == "=" && ${#args[@]} -gt 0 ]]; then
+            args[${#args[@]}-1]+="="
+            joined=1
+            argopt="$lastopt="
+            continue
+        fi
+        if (( joined )); then
+            args[${#args[@]}-1]+="$w"
+            joined=0
+            argopt=""
+            argn=$(( $(%[1]s_comp_arity "$cmd" "$lastopt") - 1 ))
+            if (( argn > 0 )); then
+                argopt="$lastopt"
+            fi
+            continue
+        fi
+        args+=("$w")
+        if [[ -n "$argopt" ]]; then
+            argn=$(( argn - 1 ))
+            if (( argn <= 0 )); then
+                argopt=""
+            fi
+            continue
+        fi
+        if (( ! nonopt )); then
+            case "$w" in
+            -) ;;
+            --)
+                nonopt=1
+                continue
+                ;;
+            -*)
+                if [[ "$w" != --* ]]; then
+                    w="-${w: -1}"
+                fi
+                lastopt="$w"
+                if %[1]s_comp_hint "$cmd" "$w" >/dev/null; then
+                    argopt="$w"
+                    argn="$(%[1]s_comp_arity "$cmd" "$w")"
+                fi
+                continue
+                ;;
+            esac
+        fi
+        next="$(%[1]s_comp_subcmd "$cmd" "$w")"
+        if [[ -n "$next" ]]; then
+            cmd="$next"
+            nonopt=0
+        fi
+    done
+
+    word="$cur"
+    if [[ "$cur" == "=" && ${#args[@]} -gt 0 ]]; then
+        argopt="$lastopt="
+        cur=""
+        word="${args[${#args[@]}-1]}="
+        unset 'args[${#args[@]}-1]'
+    elif (( joined )); then
+        word="${args[${#args[@]}-1]}$cur"
+        unset 'args[${#args[@]}-1]'
+    fi
+
+    if [[ -n "$argopt" ]]; then
+        local hint="$(%[1]s_comp_hint "$cmd" "$argopt" ||
+            %[1]s_comp_hint "$cmd" "${argopt%%=}")"
+        case "$hint" in
+        file) COMPREPLY=( $(compgen -f -- "$cur") ) ;;
+        dir) COMPREPLY=( $(compgen -d -- "$cur") ) ;;
+        call) %[1]s_comp_call "${args[@]}" "$word" ;;
+        words\ *) COMPREPLY=( $(compgen -W "${hint#words }" -- "$cur") ) ;;
+        esac
+        return 0
+    fi
+
+    if (( ! nonopt )) && [[ "$cur" == -* ]]; then
+        COMPREPLY=( $(compgen -W "$(%[1]s_comp_words "$cmd")" -- "$cur") )
+        return 0
+    fi
+
+    local subcmds="$(%[1]s_comp_subcmds "$cmd")"
+    if [[ -n "$subcmds" ]]; then
+        COMPREPLY=( $(compgen -W "$subcmds" -- "$cur") )
+        return 0
+    fi
+
+    case "$(%[1]s_comp_args "$cmd")" in
+    call) %[1]s_comp_call "${args[@]}" "$word" ;;
+    *) COMPREPLY=( $(compgen -f -- "$cur") ) ;;
+    esac
+}
+
+%[1]s_comp_call() {
+    local line
+    while IFS= read -r line; do
+        COMPREPLY+=("$line")
+    done < <("${COMP_WORDS[0]}" __complete "$@" 2>/dev/null)
+}
+
+complete -F %[1]s_comp %[3]s
+`, fn, bashQuote(cmdCfg.Name), cmdCfg.Name)
+
+	return sb.String()
+}
+
+func bashCompHint(cfg OptCfg) string {
+	if cfg.OnComplete != nil {
+		return "call"
+	}
+	if len(cfg.Choices) > 0 {
+		return bashQuote("words " + strings.Join(cfg.Choices, " "))
+	}
+	switch cfg.CompHint {
+	case COMP_FILE:
+		return "file"
+	case COMP_DIR:
+		return "dir"
+	default:
+		return "none"
+	}
+}
+
+func bashQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,246 @@
+package cliargs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sttk/cliargs"
+)
+
+func TestMakeBashCompletion_optCfgsOnly(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "foo-bar", Aliases: []string{"f"}},
+			cliargs.OptCfg{Name: "baz", HasArg: true},
+			cliargs.OptCfg{Name: "qux", HasArg: true, CompHint: cliargs.COMP_FILE},
+			cliargs.OptCfg{Name: "quux", HasArg: true, CompHint: cliargs.COMP_DIR},
+			cliargs.OptCfg{Name: "*"},
+		},
+	}
+
+	script := cliargs.MakeBashCompletion(cmdCfg)
+
+	assert.True(t, strings.HasPrefix(script, "# bash completion for app\n"))
+	assert.True(t, strings.Contains(script, `_app_comp_subcmd() {
+    case "$1 $2" in
+    esac
+}
+`))
+	assert.True(t, strings.Contains(script, `_app_comp_words() {
+    case "$1" in
+    'app') echo '--foo-bar -f --baz --qux --quux' ;;
+    esac
+}
+`))
+	assert.True(t, strings.Contains(script, `_app_comp_subcmds() {
+    case "$1" in
+    esac
+}
+`))
+	assert.True(t, strings.Contains(script, `_app_comp_hint() {
+    case "$1 $2" in
+    'app --baz') echo none ;;
+    'app --qux') echo file ;;
+    'app --quux') echo dir ;;
+    *) return 1 ;;
+    esac
+}
+`))
+	assert.True(t, strings.HasSuffix(script, "\ncomplete -F _app_comp app\n"))
+}
+
+func TestMakeBashCompletion_withSubCmds(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "my-app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}, IsGlobal: true},
+			cliargs.OptCfg{Name: "config", HasArg: true, IsGlobal: true},
+			cliargs.OptCfg{Name: "help"},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name:    "remote",
+				Aliases: []string{"rm"},
+				SubCmds: []cliargs.CmdCfg{
+					cliargs.CmdCfg{
+						Name: "add",
+						OptCfgs: []cliargs.OptCfg{
+							cliargs.OptCfg{Name: "f"},
+						},
+					},
+				},
+			},
+			cliargs.CmdCfg{Name: "list"},
+		},
+	}
+
+	script := cliargs.MakeBashCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script, `_my_app_comp_subcmd() {
+    case "$1 $2" in
+    'my-app remote'|'my-app rm') echo 'my-app remote' ;;
+    'my-app list') echo 'my-app list' ;;
+    'my-app remote add') echo 'my-app remote add' ;;
+    esac
+}
+`))
+	assert.True(t, strings.Contains(script, `_my_app_comp_words() {
+    case "$1" in
+    'my-app') echo '--verbose -v --config --help' ;;
+    'my-app remote') echo '--verbose -v --config' ;;
+    'my-app remote add') echo '--verbose -v --config -f' ;;
+    'my-app list') echo '--verbose -v --config' ;;
+    esac
+}
+`))
+	assert.True(t, strings.Contains(script, `_my_app_comp_subcmds() {
+    case "$1" in
+    'my-app') echo 'remote rm list' ;;
+    'my-app remote') echo 'add' ;;
+    esac
+}
+`))
+	assert.True(t, strings.Contains(script, `_my_app_comp_hint() {
+    case "$1 $2" in
+    'my-app --config') echo none ;;
+    'my-app remote --config') echo none ;;
+    'my-app remote add --config') echo none ;;
+    'my-app list --config') echo none ;;
+    *) return 1 ;;
+    esac
+}
+`))
+	assert.True(t, strings.HasSuffix(script, "\ncomplete -F _my_app_comp my-app\n"))
+}
+
+func TestMakeBashCompletion_onComplete(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:       "color",
+				HasArg:     true,
+				OnComplete: cliargs.CompleteChoices("always", "never"),
+			},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name:       "run",
+				OnComplete: cliargs.CompleteChoices("alpha", "beta"),
+			},
+			cliargs.CmdCfg{Name: "list"},
+		},
+	}
+
+	script := cliargs.MakeBashCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script, `_app_comp_hint() {
+    case "$1 $2" in
+    'app --color') echo call ;;
+    *) return 1 ;;
+    esac
+}
+`))
+	assert.True(t, strings.Contains(script, `_app_comp_args() {
+    case "$1" in
+    'app run') echo call ;;
+    *) echo file ;;
+    esac
+}
+`))
+	assert.True(t, strings.Contains(script,
+		`done < <("${COMP_WORDS[0]}" __complete "$@" 2>/dev/null)`))
+}
+
+func TestMakeBashCompletion_optionalArg(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:          "color",
+				Aliases:       []string{"c"},
+				HasArg:        true,
+				IsArgOptional: true,
+				CompHint:      cliargs.COMP_FILE,
+			},
+		},
+	}
+
+	script := cliargs.MakeBashCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script, `_app_comp_hint() {
+    case "$1 $2" in
+    'app --color='|'app -c=') echo file ;;
+    *) return 1 ;;
+    esac
+}
+`))
+}
+
+func TestMakeBashCompletion_choices(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:    "format",
+				Aliases: []string{"f"},
+				HasArg:  true,
+				Choices: []string{"json", "yaml"},
+			},
+		},
+	}
+
+	script := cliargs.MakeBashCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script,
+		"    'app --format'|'app -f') echo 'words json yaml' ;;\n"))
+	assert.True(t, strings.Contains(script,
+		`words\ *) COMPREPLY=( $(compgen -W "${hint#words }" -- "$cur") ) ;;`))
+}
+
+func TestMakeBashCompletion_negatableOpt(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "color", Aliases: []string{"c"}, IsNegatable: true},
+		},
+	}
+
+	script := cliargs.MakeBashCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script,
+		"    'app') echo '--color -c --no-color' ;;\n"))
+}
+
+func TestMakeBashCompletion_arity(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:    "point",
+				Aliases: []string{"p"},
+				HasArg:  true,
+				Arity:   2,
+			},
+			cliargs.OptCfg{Name: "output", HasArg: true},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{Name: "build"},
+		},
+	}
+
+	script := cliargs.MakeBashCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script, `_app_comp_arity() {
+    case "$1 $2" in
+    'app --point'|'app -p') echo 2 ;;
+    *) echo 1 ;;
+    esac
+}
+`))
+	assert.True(t, strings.Contains(script,
+		`argn="$(_app_comp_arity "$cmd" "$w")"`))
+}
@@ -0,0 +1,253 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MakeFishCompletion is a function which generates a fish completion script,
+// which consists of complete commands, from a command configuration.
+// The Name of the command configuration is used as the command name which is
+// completed, so it is required.
+//
+// Each option is completed with the first line of its Desc.
+// The name and the aliases of an option are put in one complete command, and
+// an option which is neither an array nor a counter is not offered again after
+// it is specified.
+// The negated names of a negatable option, like --no-color, are put in another
+// complete command.
+// The option argument is completed with its Choices or according to its
+// CompHint, and the option arguments of an option of which Arity is greater
+// than one are not taken as sub commands.
+// The names and aliases of the sub commands are completed with the first line
+// of their Desc.
+//
+// If OnComplete of an option configuration or a command configuration is
+// specified, the script calls back the hidden "__complete" sub command of the
+// command to obtain candidates. (See RunCompletion function.)
+func MakeFishCompletion(cmdCfg CmdCfg) string {
+	nodes := collectCompNodes(cmdCfg)
+	fn := "__" + shellIdent(cmdCfg.Name)
+	name := cmdCfg.Name
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# fish completion for %s\n", name)
+	sb.WriteString("# This script is generated by github.com/sttk/cliargs.\n\n")
+
+	fmt.Fprintf(&sb, "function %s_comp_subcmd\n", fn)
+	sb.WriteString("    switch \"$argv[1] $argv[2]\"\n")
+	for _, node := range nodes {
+		for _, sub := range node.cmdCfg.SubCmds {
+			pats := make([]string, 0, len(sub.Aliases)+1)
+			for _, subName := range subCmdNames(sub) {
+				pats = append(pats, fishQuote(node.path+" "+subName))
+			}
+			fmt.Fprintf(&sb, "        case %s\n", strings.Join(pats, " "))
+			fmt.Fprintf(&sb, "            echo %s\n", fishQuote(node.path+" "+sub.Name))
+		}
+	}
+	sb.WriteString("    end\n")
+	sb.WriteString("end\n\n")
+
+	// The options of each command are grouped by the numbers of option
+	// arguments which they take, in order of appearance.
+	fmt.Fprintf(&sb, "function %s_comp_arity\n", fn)
+	sb.WriteString("    switch \"$argv[1] $argv[2]\"\n")
+	for _, node := range nodes {
+		arities := make([]int, 0)
+		patsMap := make(map[int][]string)
+		for _, cfg := range node.optCfgs {
+			if !cfg.HasArg || cfg.IsArgOptional {
+				continue
+			}
+			n := arityOf(cfg)
+			if _, exists := patsMap[n]; !exists {
+				arities = append(arities, n)
+			}
+			for _, optName := range optNames(cfg) {
+				patsMap[n] = append(patsMap[n], fishQuote(node.path+" "+optName))
+			}
+		}
+		for _, n := range arities {
+			fmt.Fprintf(&sb, "        case %s\n", strings.Join(patsMap[n], " "))
+			fmt.Fprintf(&sb, "            echo %d\n", n)
+		}
+	}
+	sb.WriteString("    end\n")
+	sb.WriteString("end\n\n")
+
+	fmt.Fprintf(&sb, `function %[1]s_comp_path
+    set -l words (commandline -opc)
+    set -l path %[2]s
+    set -l nonopt 0
+    set -l skip 0
+    for w in $words[2..-1]
+        if test $skip -gt 0
+            set skip (math $skip - 1)
+            continue
+        end
+        if test $nonopt -eq 0
+            switch $w
+                case '-'
+                case '--'
+                    set nonopt 1
+                    continue
+                case '-*=*'
+                    set -l o (string split -m 1 = -- $w)[1]
+                    if not string match -q -- '--*' $o
+                        set o -(string sub -s -1 -- $o)
+                    end
+                    set -l n (%[1]s_comp_arity $path $o)
+                    if test -n "$n"
+                        set skip (math $n - 1)
+                    end
+                    continue
+                case '--*'
+                    set -l n (%[1]s_comp_arity $path $w)
+                    if test -n "$n"
+                        set skip $n
+                    end
+                    continue
+                case '-*'
+                    set -l n (%[1]s_comp_arity $path -(string sub -s -1 -- $w))
+                    if test -n "$n"
+                        set skip $n
+                    end
+                    continue
+            end
+        end
+        set -l next (%[1]s_comp_subcmd $path $w)
+        if test -n "$next"
+            set path $next
+            set nonopt 0
+        end
+    end
+    echo $path
+end
+
+function %[1]s_comp_is
+    test (%[1]s_comp_path) = $argv[1]
+end
+`, fn, fishQuote(name))
+
+	if hasOnComplete(nodes) {
+		fmt.Fprintf(&sb, `
+function %s_comp_call
+    set -l words (commandline -opc)
+    $words[1] __complete $words[2..-1] (commandline -ct) 2>/dev/null
+end
+`, fn)
+	}
+
+	for _, node := range nodes {
+		sb.WriteString("\n")
+		cond := fn + "_comp_is " + fishQuote(node.path)
+
+		if len(node.cmdCfg.SubCmds) > 0 {
+			fmt.Fprintf(&sb, "complete -c %s -n %s -f\n", name, fishQuote(cond))
+		} else if node.cmdCfg.OnComplete != nil {
+			fmt.Fprintf(&sb, "complete -c %s -n %s -f -a %s\n", name,
+				fishQuote(cond), fishQuote("("+fn+"_comp_call)"))
+		}
+
+		for _, cfg := range node.optCfgs {
+			c := cond
+			if !isRepeatableOpt(cfg) {
+				c += "; and not __fish_contains_opt" + fishContainsOptArgs(cfg)
+			}
+			line := "complete -c " + name + " -n " + fishQuote(c)
+			for _, optName := range append([]string{cfg.Name}, cfg.Aliases...) {
+				switch len(optName) {
+				case 0:
+				case 1:
+					line += " -s " + optName
+				default:
+					line += " -l " + optName
+				}
+			}
+			if cfg.HasArg {
+				line += fishCompAction(cfg, fn+"_comp_call")
+			}
+			desc := firstLine(cfg.Desc)
+			if len(desc) > 0 {
+				line += " -d " + fishQuote(desc)
+			}
+			sb.WriteString(line + "\n")
+
+			if negNames := negOptNames(cfg); len(negNames) > 0 {
+				line = "complete -c " + name + " -n " + fishQuote(c)
+				for _, negName := range negNames {
+					line += " -l " + negName[2:]
+				}
+				if len(desc) > 0 {
+					line += " -d " + fishQuote(desc)
+				}
+				sb.WriteString(line + "\n")
+			}
+		}
+
+		for _, sub := range node.cmdCfg.SubCmds {
+			desc := firstLine(sub.Desc)
+			for _, subName := range subCmdNames(sub) {
+				line := "complete -c " + name + " -n " + fishQuote(cond) +
+					" -f -a " + fishQuote(subName)
+				if len(desc) > 0 {
+					line += " -d " + fishQuote(desc)
+				}
+				sb.WriteString(line + "\n")
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+func fishContainsOptArgs(cfg OptCfg) string {
+	var shorts, longs string
+	for _, optName := range append([]string{cfg.Name}, cfg.Aliases...) {
+		switch len(optName) {
+		case 0:
+		case 1:
+			shorts += " -s " + optName
+		default:
+			longs += " " + optName
+		}
+	}
+	for _, negName := range negOptNames(cfg) {
+		longs += " " + negName[2:]
+	}
+	return shorts + longs
+}
+
+func fishCompAction(cfg OptCfg, callFn string) string {
+	// An optional option argument is not required with -r, so it is completed
+	// only after "=".
+	req, excl := " -r", " -x"
+	if cfg.IsArgOptional {
+		req, excl = "", " -f"
+	}
+	if cfg.OnComplete != nil {
+		return excl + " -a " + fishQuote("("+callFn+")")
+	}
+	if len(cfg.Choices) > 0 {
+		return excl + " -a " + fishQuote(strings.Join(cfg.Choices, " "))
+	}
+	switch cfg.CompHint {
+	case COMP_FILE:
+		return req + " -F"
+	case COMP_DIR:
+		return excl + " -a '(__fish_complete_directories)'"
+	default:
+		return excl
+	}
+}
+
+func fishQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
@@ -0,0 +1,251 @@
+package cliargs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sttk/cliargs"
+)
+
+func TestMakeFishCompletion_optCfgsOnly(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:    "foo-bar",
+				Aliases: []string{"f"},
+				Desc:    "FooBar is a flag.\nThis flag is foo bar.",
+			},
+			cliargs.OptCfg{
+				Name:    "baz",
+				HasArg:  true,
+				IsArray: true,
+				Desc:    "Baz's description",
+			},
+			cliargs.OptCfg{
+				Name:     "qux",
+				Aliases:  []string{"q"},
+				HasArg:   true,
+				CompHint: cliargs.COMP_FILE,
+			},
+			cliargs.OptCfg{
+				Name:     "quux",
+				HasArg:   true,
+				CompHint: cliargs.COMP_DIR,
+			},
+			cliargs.OptCfg{Name: "*"},
+		},
+	}
+
+	script := cliargs.MakeFishCompletion(cmdCfg)
+
+	assert.True(t, strings.HasPrefix(script, "# fish completion for app\n"))
+	assert.True(t, strings.Contains(script, `
+function __app_comp_arity
+    switch "$argv[1] $argv[2]"
+        case 'app --baz' 'app --qux' 'app -q' 'app --quux'
+            echo 1
+    end
+end
+`))
+	assert.True(t, strings.HasSuffix(script, `
+complete -c app -n '__app_comp_is \'app\'; and not __fish_contains_opt -s f foo-bar' -l foo-bar -s f -d 'FooBar is a flag.'
+complete -c app -n '__app_comp_is \'app\'' -l baz -x -d 'Baz\'s description'
+complete -c app -n '__app_comp_is \'app\'; and not __fish_contains_opt -s q qux' -l qux -s q -r -F
+complete -c app -n '__app_comp_is \'app\'; and not __fish_contains_opt quux' -l quux -x -a '(__fish_complete_directories)'
+`))
+}
+
+func TestMakeFishCompletion_withSubCmds(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}, IsGlobal: true},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name:    "list",
+				Aliases: []string{"ls"},
+				Desc:    "List items",
+				OptCfgs: []cliargs.OptCfg{
+					cliargs.OptCfg{Name: "all", Aliases: []string{"a"}},
+				},
+			},
+		},
+	}
+
+	script := cliargs.MakeFishCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script, `
+function __app_comp_subcmd
+    switch "$argv[1] $argv[2]"
+        case 'app list' 'app ls'
+            echo 'app list'
+    end
+end
+`))
+	assert.True(t, strings.HasSuffix(script, `
+complete -c app -n '__app_comp_is \'app\'' -f
+complete -c app -n '__app_comp_is \'app\'; and not __fish_contains_opt -s v verbose' -l verbose -s v
+complete -c app -n '__app_comp_is \'app\'' -f -a 'list' -d 'List items'
+complete -c app -n '__app_comp_is \'app\'' -f -a 'ls' -d 'List items'
+
+complete -c app -n '__app_comp_is \'app list\'; and not __fish_contains_opt -s v verbose' -l verbose -s v
+complete -c app -n '__app_comp_is \'app list\'; and not __fish_contains_opt -s a all' -l all -s a
+`))
+}
+
+func TestMakeFishCompletion_onComplete(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:       "color",
+				HasArg:     true,
+				OnComplete: cliargs.CompleteChoices("always", "never"),
+			},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name:       "run",
+				OnComplete: cliargs.CompleteChoices("alpha", "beta"),
+			},
+		},
+	}
+
+	script := cliargs.MakeFishCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script, `
+function __app_comp_call
+    set -l words (commandline -opc)
+    $words[1] __complete $words[2..-1] (commandline -ct) 2>/dev/null
+end
+`))
+	assert.True(t, strings.Contains(script, `
+complete -c app -n '__app_comp_is \'app\'; and not __fish_contains_opt color' -l color -x -a '(__app_comp_call)'
+`))
+	assert.True(t, strings.Contains(script, `
+complete -c app -n '__app_comp_is \'app run\'' -f -a '(__app_comp_call)'
+`))
+}
+
+func TestMakeFishCompletion_optionalArg(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:          "color",
+				HasArg:        true,
+				IsArgOptional: true,
+				OnComplete:    cliargs.CompleteChoices("always", "never"),
+			},
+			cliargs.OptCfg{
+				Name:          "log",
+				HasArg:        true,
+				IsArgOptional: true,
+				CompHint:      cliargs.COMP_FILE,
+			},
+		},
+	}
+
+	script := cliargs.MakeFishCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script,
+		"-l color -f -a '(__app_comp_call)'\n"))
+	assert.True(t, strings.Contains(script, "-l log -F\n"))
+	assert.False(t, strings.Contains(script, "case 'app --color'"))
+}
+
+func TestMakeFishCompletion_choices(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:    "format",
+				HasArg:  true,
+				Choices: []string{"json", "yaml"},
+			},
+		},
+	}
+
+	script := cliargs.MakeFishCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script, "-l format -x -a 'json yaml'\n"))
+}
+
+func TestMakeFishCompletion_counter(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}, IsCounter: true},
+			cliargs.OptCfg{Name: "quiet", Aliases: []string{"q"}, Decrements: "verbose"},
+			cliargs.OptCfg{Name: "force"},
+		},
+	}
+
+	script := cliargs.MakeFishCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script,
+		"complete -c app -n '__app_comp_is \\'app\\'' -l verbose -s v\n"))
+	assert.True(t, strings.Contains(script,
+		"complete -c app -n '__app_comp_is \\'app\\'' -l quiet -s q\n"))
+	assert.True(t, strings.Contains(script,
+		"complete -c app -n '__app_comp_is \\'app\\'; and not __fish_contains_opt force' -l force\n"))
+}
+
+func TestMakeFishCompletion_negatableOpt(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "color", Aliases: []string{"c"}, IsNegatable: true},
+		},
+	}
+
+	script := cliargs.MakeFishCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script,
+		"complete -c app -n '__app_comp_is \\'app\\'; and not __fish_contains_opt -s c color no-color' -l color -s c\n"))
+	assert.True(t, strings.Contains(script,
+		"complete -c app -n '__app_comp_is \\'app\\'; and not __fish_contains_opt -s c color no-color' -l no-color\n"))
+}
+
+func TestMakeFishCompletion_arity(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:    "point",
+				Aliases: []string{"p"},
+				HasArg:  true,
+				Arity:   2,
+			},
+			cliargs.OptCfg{Name: "output", HasArg: true},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{Name: "build"},
+		},
+	}
+
+	script := cliargs.MakeFishCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script, `
+function __app_comp_arity
+    switch "$argv[1] $argv[2]"
+        case 'app --point' 'app -p'
+            echo 2
+        case 'app --output'
+            echo 1
+    end
+end
+`))
+	assert.True(t, strings.Contains(script, `
+                case '--*'
+                    set -l n (__app_comp_arity $path $w)
+                    if test -n "$n"
+                        set skip $n
+                    end
+                    continue
+`))
+}
@@ -0,0 +1,215 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MakeZshCompletion is a function which generates a zsh completion script,
+// which uses _arguments function, from a command configuration.
+// The Name of the command configuration is used as the command name which is
+// completed, so it is required.
+//
+// Each option is completed with the first line of its Desc.
+// The name and the aliases of an option are mutually exclusive, and an option
+// which is neither an array nor a counter is not offered again after it is
+// specified.
+// The negated names of a negatable option, like --no-color, are also
+// completed, and are mutually exclusive with its names.
+// The option argument is completed with its Choices or according to its
+// CompHint, and ArgHelp is displayed as the message of the option argument.
+// The names and aliases of the sub commands are completed with the first line
+// of their Desc.
+//
+// If OnComplete of an option configuration or a command configuration is
+// specified, the script calls back the hidden "__complete" sub command of the
+// command to obtain candidates. (See RunCompletion function.)
+func MakeZshCompletion(cmdCfg CmdCfg) string {
+	nodes := collectCompNodes(cmdCfg)
+	fn := "_" + shellIdent(cmdCfg.Name)
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "#compdef %s\n", cmdCfg.Name)
+	fmt.Fprintf(&sb, "# zsh completion for %s\n", cmdCfg.Name)
+	sb.WriteString("# This script is generated by github.com/sttk/cliargs.\n")
+
+	for _, node := range nodes {
+		sb.WriteString("\n")
+		writeZshFunc(&sb, "_"+shellIdent(node.path), node, fn+"_comp_call")
+	}
+
+	if hasOnComplete(nodes) {
+		fmt.Fprintf(&sb, `
+%s_comp_call() {
+  local -a words cands
+  words=(${(Q)${(z)LBUFFER}})
+  [[ "$LBUFFER" == *[[:space:]] ]] && words+=("")
+  cands=(${(f)"$(${words[1]} __complete "${(@)words[2,-1]}" 2>/dev/null)"})
+  compadd -Q -S '' -a cands
+}
+`, fn)
+	}
+
+	fmt.Fprintf(&sb, `
+if [ "$funcstack[1]" = "%[1]s" ]; then
+  %[1]s "$@"
+else
+  compdef %[1]s %[2]s
+fi
+`, fn, cmdCfg.Name)
+
+	return sb.String()
+}
+
+func writeZshFunc(sb *strings.Builder, fn string, node compNode, callFn string) {
+	hasSubCmds := len(node.cmdCfg.SubCmds) > 0
+
+	fmt.Fprintf(sb, "%s() {\n", fn)
+	if hasSubCmds {
+		sb.WriteString("  local curcontext=\"$curcontext\" state line\n")
+		sb.WriteString("  typeset -A opt_args\n\n")
+		sb.WriteString("  _arguments -s -S -C")
+	} else {
+		sb.WriteString("  _arguments -s -S")
+	}
+
+	for _, cfg := range node.optCfgs {
+		for _, spec := range zshOptSpecs(cfg, callFn) {
+			sb.WriteString(" \\\n    " + zshQuote(spec))
+		}
+	}
+
+	if hasSubCmds {
+		sb.WriteString(" \\\n    '1: :->subcmds'")
+		sb.WriteString(" \\\n    '*:: :->args'\n\n")
+
+		sb.WriteString("  case $state in\n")
+		sb.WriteString("  subcmds)\n")
+		sb.WriteString("    local -a subcmds\n")
+		sb.WriteString("    subcmds=(\n")
+		for _, sub := range node.cmdCfg.SubCmds {
+			desc := zshEscapeColon(firstLine(sub.Desc))
+			for _, name := range subCmdNames(sub) {
+				item := zshEscapeColon(name)
+				if len(desc) > 0 {
+					item += ":" + desc
+				}
+				sb.WriteString("      " + zshQuote(item) + "\n")
+			}
+		}
+		sb.WriteString("    )\n")
+		sb.WriteString("    _describe -t commands 'command' subcmds\n")
+		sb.WriteString("    ;;\n")
+		sb.WriteString("  args)\n")
+		sb.WriteString("    case $line[1] in\n")
+		for _, sub := range node.cmdCfg.SubCmds {
+			pats := make([]string, 0, len(sub.Aliases)+1)
+			for _, name := range subCmdNames(sub) {
+				pats = append(pats, zshQuote(name))
+			}
+			fmt.Fprintf(sb, "    %s) _%s ;;\n", strings.Join(pats, "|"),
+				shellIdent(node.path+" "+sub.Name))
+		}
+		sb.WriteString("    esac\n")
+		sb.WriteString("    ;;\n")
+		sb.WriteString("  esac\n")
+	} else if node.cmdCfg.OnComplete != nil {
+		sb.WriteString(" \\\n    " + zshQuote("*:arg:"+callFn) + "\n")
+	} else {
+		sb.WriteString(" \\\n    '*:file:_files'\n")
+	}
+
+	sb.WriteString("}\n")
+}
+
+func zshOptSpecs(cfg OptCfg, callFn string) []string {
+	// A negatable option takes no option argument, so its negated names are
+	// specified in the same way as its names.
+	names := append(optNames(cfg), negOptNames(cfg)...)
+
+	var excl string
+	if isRepeatableOpt(cfg) {
+		excl = "*"
+	} else {
+		excl = "(" + strings.Join(names, " ") + ")"
+	}
+
+	desc := zshEscapeBracket(firstLine(cfg.Desc))
+
+	var arg string
+	if cfg.HasArg {
+		msg := optArgHelp(cfg)
+		if len(msg) == 0 {
+			msg = "value"
+		}
+		// An optional option argument is given only after "=".
+		if cfg.IsArgOptional {
+			arg = "=-[" + desc + "]::"
+		} else {
+			arg = "=[" + desc + "]:"
+		}
+		arg += zshEscapeColon(msg) + ":" + zshCompAction(cfg, callFn)
+	} else {
+		arg = "[" + desc + "]"
+	}
+
+	specs := make([]string, len(names))
+	for i, name := range names {
+		specs[i] = excl + name + arg
+	}
+	return specs
+}
+
+func zshCompAction(cfg OptCfg, callFn string) string {
+	if cfg.OnComplete != nil {
+		return callFn
+	}
+	if len(cfg.Choices) > 0 {
+		words := make([]string, len(cfg.Choices))
+		for i, c := range cfg.Choices {
+			words[i] = zshEscapeChoice(c)
+		}
+		return "(" + strings.Join(words, " ") + ")"
+	}
+	switch cfg.CompHint {
+	case COMP_FILE:
+		return "_files"
+	case COMP_DIR:
+		return "_files -/"
+	default:
+		return "( )"
+	}
+}
+
+// zshEscapeChoice is a function which escapes a word in a list of choices of
+// an action of _arguments.
+func zshEscapeChoice(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(" ()\\:'\"$`[]", r) {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func zshEscapeBracket(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "[", `\[`)
+	return strings.ReplaceAll(s, "]", `\]`)
+}
+
+func zshEscapeColon(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, ":", `\:`)
+}
+
+func zshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,251 @@
+package cliargs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sttk/cliargs"
+)
+
+func TestMakeZshCompletion_optCfgsOnly(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:    "foo-bar",
+				Aliases: []string{"f"},
+				Desc:    "FooBar is a flag.\nThis flag is foo bar.",
+			},
+			cliargs.OptCfg{
+				Name:     "baz",
+				HasArg:   true,
+				IsArray:  true,
+				Desc:     "Baz's [description]",
+				ArgHelp:  "<num>",
+				CompHint: cliargs.COMP_NONE,
+			},
+			cliargs.OptCfg{
+				Name:     "qux",
+				Aliases:  []string{"q"},
+				HasArg:   true,
+				CompHint: cliargs.COMP_FILE,
+			},
+			cliargs.OptCfg{
+				Name:     "quux",
+				HasArg:   true,
+				ArgHelp:  "<dir:path>",
+				CompHint: cliargs.COMP_DIR,
+			},
+			cliargs.OptCfg{Name: "*"},
+		},
+	}
+
+	script := cliargs.MakeZshCompletion(cmdCfg)
+
+	assert.Equal(t, script, `#compdef app
+# zsh completion for app
+# This script is generated by github.com/sttk/cliargs.
+
+_app() {
+  _arguments -s -S \
+    '(--foo-bar -f)--foo-bar[FooBar is a flag.]' \
+    '(--foo-bar -f)-f[FooBar is a flag.]' \
+    '*--baz=[Baz'\''s \[description\]]:<num>:( )' \
+    '(--qux -q)--qux=[]:value:_files' \
+    '(--qux -q)-q=[]:value:_files' \
+    '(--quux)--quux=[]:<dir\:path>:_files -/' \
+    '*:file:_files'
+}
+
+if [ "$funcstack[1]" = "_app" ]; then
+  _app "$@"
+else
+  compdef _app app
+fi
+`)
+}
+
+func TestMakeZshCompletion_withSubCmds(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}, IsGlobal: true},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name:    "list",
+				Aliases: []string{"ls"},
+				Desc:    "List: items",
+				OptCfgs: []cliargs.OptCfg{
+					cliargs.OptCfg{Name: "all", Aliases: []string{"a"}},
+				},
+			},
+			cliargs.CmdCfg{Name: "use"},
+		},
+	}
+
+	script := cliargs.MakeZshCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script, `
+_app() {
+  local curcontext="$curcontext" state line
+  typeset -A opt_args
+
+  _arguments -s -S -C \
+    '(--verbose -v)--verbose[]' \
+    '(--verbose -v)-v[]' \
+    '1: :->subcmds' \
+    '*:: :->args'
+
+  case $state in
+  subcmds)
+    local -a subcmds
+    subcmds=(
+      'list:List\: items'
+      'ls:List\: items'
+      'use'
+    )
+    _describe -t commands 'command' subcmds
+    ;;
+  args)
+    case $line[1] in
+    'list'|'ls') _app_list ;;
+    'use') _app_use ;;
+    esac
+    ;;
+  esac
+}
+`))
+	assert.True(t, strings.Contains(script, `
+_app_list() {
+  _arguments -s -S \
+    '(--verbose -v)--verbose[]' \
+    '(--verbose -v)-v[]' \
+    '(--all -a)--all[]' \
+    '(--all -a)-a[]' \
+    '*:file:_files'
+}
+`))
+	assert.True(t, strings.Contains(script, `
+_app_use() {
+  _arguments -s -S \
+    '(--verbose -v)--verbose[]' \
+    '(--verbose -v)-v[]' \
+    '*:file:_files'
+}
+`))
+}
+
+func TestMakeZshCompletion_onComplete(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:       "color",
+				HasArg:     true,
+				OnComplete: cliargs.CompleteChoices("always", "never"),
+			},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name:       "run",
+				OnComplete: cliargs.CompleteChoices("alpha", "beta"),
+			},
+		},
+	}
+
+	script := cliargs.MakeZshCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script,
+		`'(--color)--color=[]:value:_app_comp_call'`))
+	assert.True(t, strings.Contains(script, `
+_app_run() {
+  _arguments -s -S \
+    '*:arg:_app_comp_call'
+}
+`))
+	assert.True(t, strings.Contains(script, `
+_app_comp_call() {
+  local -a words cands
+`))
+}
+
+func TestMakeZshCompletion_optionalArg(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:          "color",
+				HasArg:        true,
+				IsArgOptional: true,
+				ArgHelp:       "<when>",
+				Desc:          "Colorize output.",
+			},
+		},
+	}
+
+	script := cliargs.MakeZshCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script,
+		`'(--color)--color=-[Colorize output.]::<when>:( )'`))
+}
+
+func TestMakeZshCompletion_choices(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:    "format",
+				HasArg:  true,
+				Choices: []string{"json", "a b"},
+			},
+		},
+	}
+
+	script := cliargs.MakeZshCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script,
+		`'(--format)--format=[]:{json|a b}:(json a\ b)'`))
+}
+
+func TestMakeZshCompletion_counter(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}, IsCounter: true},
+			cliargs.OptCfg{Name: "quiet", Aliases: []string{"q"}, Decrements: "verbose"},
+			cliargs.OptCfg{Name: "force"},
+		},
+	}
+
+	script := cliargs.MakeZshCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script, "'*--verbose[]'"))
+	assert.True(t, strings.Contains(script, "'*-v[]'"))
+	assert.True(t, strings.Contains(script, "'*--quiet[]'"))
+	assert.True(t, strings.Contains(script, "'*-q[]'"))
+	assert.True(t, strings.Contains(script, "'(--force)--force[]'"))
+}
+
+func TestMakeZshCompletion_negatableOpt(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:        "color",
+				Aliases:     []string{"c"},
+				IsNegatable: true,
+				Desc:        "Colorize output.",
+			},
+		},
+	}
+
+	script := cliargs.MakeZshCompletion(cmdCfg)
+
+	assert.True(t, strings.Contains(script,
+		"'(--color -c --no-color)--color[Colorize output.]'"))
+	assert.True(t, strings.Contains(script,
+		"'(--color -c --no-color)--no-color[Colorize output.]'"))
+}
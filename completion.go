@@ -0,0 +1,153 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargs
+
+import (
+	"strings"
+	"unicode"
+)
+
+// CompHint is a type which indicates what kind of values are offered as
+// candidates when completing an option argument in a shell.
+type CompHint int
+
+const (
+	COMP_NONE CompHint = iota // Offers no candidate.
+	COMP_FILE                 // Offers file paths.
+	COMP_DIR                  // Offers directory paths.
+)
+
+// compNode is a structure which holds the information of a command for
+// generating shell completion scripts.
+// path is the command names from the top command joined with spaces, and
+// optCfgs includes global options inherited from its ancestor commands.
+type compNode struct {
+	path    string
+	cmdCfg  CmdCfg
+	optCfgs []OptCfg
+}
+
+func collectCompNodes(cmdCfg CmdCfg) []compNode {
+	return appendCompNodes(make([]compNode, 0), cmdCfg, cmdCfg.Name, nil)
+}
+
+func appendCompNodes(
+	nodes []compNode, cmdCfg CmdCfg, path string, globals []OptCfg,
+) []compNode {
+	optCfgs := make([]OptCfg, 0, len(globals)+len(cmdCfg.OptCfgs))
+	subGlobals := make([]OptCfg, len(globals), len(globals)+len(cmdCfg.OptCfgs))
+	copy(subGlobals, globals)
+
+	for _, cfg := range globals {
+		if !hasOptName(cmdCfg.OptCfgs, cfg.Name) {
+			optCfgs = append(optCfgs, cfg)
+		}
+	}
+	for _, cfg := range cmdCfg.OptCfgs {
+		if cfg.Name == anyOption {
+			continue
+		}
+		optCfgs = append(optCfgs, cfg)
+		if cfg.IsGlobal {
+			subGlobals = append(subGlobals, cfg)
+		}
+	}
+
+	nodes = append(nodes, compNode{path: path, cmdCfg: cmdCfg, optCfgs: optCfgs})
+
+	for _, sub := range cmdCfg.SubCmds {
+		nodes = appendCompNodes(nodes, sub, path+" "+sub.Name, subGlobals)
+	}
+	return nodes
+}
+
+func hasOnComplete(nodes []compNode) bool {
+	for _, node := range nodes {
+		if node.cmdCfg.OnComplete != nil && len(node.cmdCfg.SubCmds) == 0 {
+			return true
+		}
+		for _, cfg := range node.optCfgs {
+			if cfg.HasArg && cfg.OnComplete != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasOptName(optCfgs []OptCfg, name string) bool {
+	for _, cfg := range optCfgs {
+		if cfg.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func optNames(cfg OptCfg) []string {
+	names := make([]string, 0, len(cfg.Aliases)+1)
+	names = append(names, optWord(cfg.Name))
+	for _, a := range cfg.Aliases {
+		names = append(names, optWord(a))
+	}
+	return names
+}
+
+// negOptNames is a function which returns the negated forms of the name and
+// the long aliases of a negatable option, like --no-color.
+func negOptNames(cfg OptCfg) []string {
+	if !cfg.IsNegatable {
+		return nil
+	}
+	names := make([]string, 0, len(cfg.Aliases)+1)
+	for _, s := range append([]string{cfg.Name}, cfg.Aliases...) {
+		if len(s) > 1 {
+			names = append(names, "--"+negPrefix+s)
+		}
+	}
+	return names
+}
+
+// isRepeatableOpt is a function which returns true if the option can be
+// specified multiple times meaningfully, like an array option or a counter
+// option, so that it is offered again after it is specified.
+func isRepeatableOpt(cfg OptCfg) bool {
+	return cfg.IsArray || cfg.IsCounter || len(cfg.Decrements) > 0
+}
+
+func optWord(name string) string {
+	switch len(name) {
+	case 0:
+		return ""
+	case 1:
+		return "-" + name
+	default:
+		return "--" + name
+	}
+}
+
+func subCmdNames(cfg CmdCfg) []string {
+	names := make([]string, 0, len(cfg.Aliases)+1)
+	names = append(names, cfg.Name)
+	names = append(names, cfg.Aliases...)
+	return names
+}
+
+func shellIdent(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x80 && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func firstLine(text string) string {
+	i := strings.IndexAny(text, "\r\n")
+	if i < 0 {
+		return text
+	}
+	return text[0:i]
+}
@@ -0,0 +1,225 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConfigConstraintHasUnknownOption is an error which indicates that an option
+// constraint includes an option name which is not configured.
+type ConfigConstraintHasUnknownOption struct{ Option string }
+
+func (e ConfigConstraintHasUnknownOption) Error() string {
+	return fmt.Sprintf("ConfigConstraintHasUnknownOption{Option:%s}", e.Option)
+}
+
+// ConfigImpliesOptionWithArg is an error which indicates that an option
+// constraint implies an option which takes a mandatory option argument, so
+// that the option argument of the implied option cannot be determined.
+type ConfigImpliesOptionWithArg struct{ Option, Implied string }
+
+func (e ConfigImpliesOptionWithArg) Error() string {
+	return fmt.Sprintf("ConfigImpliesOptionWithArg{Option:%s,Implied:%s}",
+		e.Option, e.Implied)
+}
+
+// OptionsAreExclusive is an error which indicates that mutually exclusive
+// options are given together in command line arguments.
+type OptionsAreExclusive struct{ Option, Other string }
+
+func (e OptionsAreExclusive) Error() string {
+	return fmt.Sprintf("OptionsAreExclusive{Option:%s,Other:%s}",
+		e.Option, e.Other)
+}
+
+// OptionRequiresOption is an error which indicates that an option is given in
+// command line arguments without an option which the option requires.
+type OptionRequiresOption struct{ Option, Required string }
+
+func (e OptionRequiresOption) Error() string {
+	return fmt.Sprintf("OptionRequiresOption{Option:%s,Required:%s}",
+		e.Option, e.Required)
+}
+
+// ConstraintKind is a type which indicates what kind of relationship an
+// option constraint defines among options.
+type ConstraintKind int
+
+const (
+	CONSTRAINT_EXCLUSIVE ConstraintKind = iota // Options are mutually exclusive.
+	CONSTRAINT_REQUIRES                        // The first option requires others.
+	CONSTRAINT_IMPLIES                         // The first option implies others.
+)
+
+// OptConstraint is a structure that represents a relationship among options.
+// An option constraint consists of fields: Kind and Options.
+//
+// If Kind is CONSTRAINT_EXCLUSIVE, at most one of Options can be given.
+// If Kind is CONSTRAINT_REQUIRES, the other options are required when the
+// first option of Options is given.
+// If Kind is CONSTRAINT_IMPLIES, the other options are regarded as given when
+// the first option of Options is given.
+//
+// Options are names or aliases of options.
+// Only the options which are given in command line arguments or are implied
+// are checked, and the options which are not given but have default values
+// are not.
+// A negated option (--no-name) is regarded as not given.
+type OptConstraint struct {
+	Kind    ConstraintKind
+	Options []string
+}
+
+// Exclusive is a function which creates an OptConstraint which makes the
+// specified options mutually exclusive, like --json and --table.
+func Exclusive(options ...string) OptConstraint {
+	return OptConstraint{Kind: CONSTRAINT_EXCLUSIVE, Options: options}
+}
+
+// Requires is a function which creates an OptConstraint which makes the
+// specified option require the other options, like --key requires --cert.
+func Requires(option string, required ...string) OptConstraint {
+	return OptConstraint{
+		Kind:    CONSTRAINT_REQUIRES,
+		Options: append([]string{option}, required...),
+	}
+}
+
+// Implies is a function which creates an OptConstraint which makes the
+// specified option imply the other options, like --all implies --recursive.
+// An implied option is registered to Cmd as if it is given without an option
+// argument before the event handlers (OptCfg#OnParsed) are called.
+// An implied option can be a flag, a counter, or an option of which option
+// argument is optional, and takes ImplicitArg in the last case.
+func Implies(option string, implied ...string) OptConstraint {
+	return OptConstraint{
+		Kind:    CONSTRAINT_IMPLIES,
+		Options: append([]string{option}, implied...),
+	}
+}
+
+// applyConstraints is a function which injects implied options into opts and
+// then checks the option constraints.
+func applyConstraints(
+	constraints []OptConstraint, optCfgs []OptCfg, opts map[string][]string,
+) error {
+	if len(constraints) == 0 {
+		return nil
+	}
+
+	cfgMap := make(map[string]int)
+	for i, cfg := range optCfgs {
+		if cfg.Name == anyOption {
+			continue
+		}
+		cfgMap[cfg.Name] = i
+		for _, a := range cfg.Aliases {
+			cfgMap[a] = i
+		}
+	}
+
+	// resolved has the indexes of the option configurations for the options of
+	// each constraint.
+	resolved := make([][]int, len(constraints))
+	for i, c := range constraints {
+		resolved[i] = make([]int, len(c.Options))
+		for j, name := range c.Options {
+			k, exists := cfgMap[name]
+			if !exists {
+				return ConfigConstraintHasUnknownOption{Option: name}
+			}
+			resolved[i][j] = k
+		}
+	}
+
+	var isGiven = func(k int) bool {
+		arr, exists := opts[optCfgs[k].Name]
+		return exists && arr != nil
+	}
+
+	// Implications are applied repeatedly because an implied option can imply
+	// other options.
+	for isChanged := true; isChanged; {
+		isChanged = false
+		for i, c := range constraints {
+			ks := resolved[i]
+			if c.Kind != CONSTRAINT_IMPLIES || len(ks) == 0 || !isGiven(ks[0]) {
+				continue
+			}
+			for _, k := range ks[1:] {
+				cfg := optCfgs[k]
+				if _, exists := opts[cfg.Name]; exists {
+					continue
+				}
+				switch {
+				case cfg.IsCounter:
+					opts[cfg.Name] = []string{"1"}
+				case !cfg.HasArg:
+					opts[cfg.Name] = empty
+				case cfg.IsArgOptional:
+					opts[cfg.Name] = []string{cfg.ImplicitArg}
+				default:
+					return ConfigImpliesOptionWithArg{
+						Option: optCfgs[ks[0]].Name, Implied: cfg.Name}
+				}
+				isChanged = true
+			}
+		}
+	}
+
+	for i, c := range constraints {
+		ks := resolved[i]
+		switch c.Kind {
+		case CONSTRAINT_EXCLUSIVE:
+			given := -1
+			for _, k := range ks {
+				if !isGiven(k) || k == given {
+					continue
+				}
+				if given >= 0 {
+					return OptionsAreExclusive{
+						Option: optCfgs[given].Name, Other: optCfgs[k].Name}
+				}
+				given = k
+			}
+		case CONSTRAINT_REQUIRES:
+			if len(ks) == 0 || !isGiven(ks[0]) {
+				continue
+			}
+			for _, k := range ks[1:] {
+				if !isGiven(k) {
+					return OptionRequiresOption{
+						Option: optCfgs[ks[0]].Name, Required: optCfgs[k].Name}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// makeConstraintText is a function which returns the description of an option
+// constraint for a help text.
+func makeConstraintText(c OptConstraint) string {
+	words := make([]string, len(c.Options))
+	for i, name := range c.Options {
+		words[i] = optWord(name)
+	}
+	if len(words) == 0 {
+		return ""
+	}
+
+	switch c.Kind {
+	case CONSTRAINT_EXCLUSIVE:
+		return strings.Join(words, " | ") + " (mutually exclusive)"
+	case CONSTRAINT_REQUIRES:
+		return words[0] + " requires " + strings.Join(words[1:], ", ")
+	case CONSTRAINT_IMPLIES:
+		return words[0] + " implies " + strings.Join(words[1:], ", ")
+	}
+	return ""
+}
@@ -0,0 +1,183 @@
+package cliargs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sttk/cliargs"
+)
+
+func TestParseWith_constraints_exclusive(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "json", Aliases: []string{"j"}},
+		cliargs.OptCfg{Name: "table", Aliases: []string{"t"}, IsNegatable: true},
+	}
+	c := cliargs.WithConstraints(cliargs.Exclusive("json", "table"))
+
+	cmd, err := cliargs.ParseWith([]string{"app", "--json"}, optCfgs, c)
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("json"))
+
+	_, err = cliargs.ParseWith([]string{"app", "-t", "-j"}, optCfgs, c)
+	assert.Equal(t, err.Error(), "OptionsAreExclusive{Option:json,Other:table}")
+	switch e := err.(type) {
+	case cliargs.OptionsAreExclusive:
+		assert.Equal(t, e.Option, "json")
+		assert.Equal(t, e.Other, "table")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	cmd, err = cliargs.ParseWith([]string{"app", "-j", "--no-table"}, optCfgs, c)
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("json"))
+	assert.False(t, cmd.HasOpt("table"))
+}
+
+func TestParseWith_constraints_requires(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "key", HasArg: true},
+		cliargs.OptCfg{Name: "cert", HasArg: true},
+	}
+	c := cliargs.WithConstraints(cliargs.Requires("key", "cert"))
+
+	_, err := cliargs.ParseWith([]string{"app", "--key", "k"}, optCfgs, c)
+	assert.Equal(t, err.Error(), "OptionRequiresOption{Option:key,Required:cert}")
+	switch e := err.(type) {
+	case cliargs.OptionRequiresOption:
+		assert.Equal(t, e.Option, "key")
+		assert.Equal(t, e.Required, "cert")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	cmd, err := cliargs.ParseWith(
+		[]string{"app", "--key", "k", "--cert", "c"}, optCfgs, c)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("cert"), "c")
+
+	_, err = cliargs.ParseWith([]string{"app", "--cert", "c"}, optCfgs, c)
+	assert.Nil(t, err)
+}
+
+func TestParseWith_constraints_requiresIgnoresDefault(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "key", HasArg: true},
+		cliargs.OptCfg{Name: "cert", HasArg: true, Default: []string{"default.pem"}},
+	}
+	c := cliargs.WithConstraints(cliargs.Requires("key", "cert"))
+
+	_, err := cliargs.ParseWith([]string{"app", "--key", "k"}, optCfgs, c)
+	assert.Equal(t, err.Error(), "OptionRequiresOption{Option:key,Required:cert}")
+}
+
+func TestParseWith_constraints_implies(t *testing.T) {
+	var recursive []string
+	onParsed := func(a []string) error {
+		recursive = a
+		return nil
+	}
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "json", Aliases: []string{"j"}},
+		cliargs.OptCfg{Name: "all", Aliases: []string{"a"}},
+		cliargs.OptCfg{
+			Name: "recursive", Aliases: []string{"r"}, OnParsed: &onParsed,
+		},
+		cliargs.OptCfg{Name: "hidden"},
+		cliargs.OptCfg{Name: "verbose", IsCounter: true},
+		cliargs.OptCfg{
+			Name: "color", HasArg: true, IsArgOptional: true, ImplicitArg: "auto",
+		},
+	}
+
+	c := cliargs.WithConstraints(
+		cliargs.Implies("a", "r", "verbose", "color"),
+		cliargs.Implies("recursive", "hidden"),
+		cliargs.Exclusive("hidden", "json"),
+	)
+
+	cmd, err := cliargs.ParseWith([]string{"app", "-a"}, optCfgs, c)
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("all"))
+	assert.True(t, cmd.HasOpt("recursive"))
+	assert.True(t, cmd.HasOpt("hidden"))
+	assert.Equal(t, cmd.OptArg("verbose"), "1")
+	assert.Equal(t, cmd.OptArg("color"), "auto")
+	assert.Equal(t, recursive, []string{})
+
+	cmd, err = cliargs.ParseWith(
+		[]string{"app", "-a", "--color=never"}, optCfgs, c)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("color"), "never")
+
+	_, err = cliargs.ParseWith([]string{"app", "-a", "-j"}, optCfgs, c)
+	assert.Equal(t, err.Error(), "OptionsAreExclusive{Option:hidden,Other:json}")
+
+	recursive = nil
+	_, err = cliargs.ParseWith([]string{"app"}, optCfgs, c)
+	assert.Nil(t, err)
+	assert.Nil(t, recursive)
+}
+
+func TestParseWith_constraints_configError(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "json"},
+		cliargs.OptCfg{Name: "all", Aliases: []string{"a"}},
+		cliargs.OptCfg{Name: "key", HasArg: true},
+	}
+
+	_, err := cliargs.ParseWith([]string{"app"}, optCfgs,
+		cliargs.WithConstraints(cliargs.Exclusive("json", "yaml")))
+	assert.Equal(t, err.Error(), "ConfigConstraintHasUnknownOption{Option:yaml}")
+
+	_, err = cliargs.ParseWith([]string{"app", "-a"}, optCfgs,
+		cliargs.WithConstraints(cliargs.Implies("all", "key")))
+	assert.Equal(t, err.Error(),
+		"ConfigImpliesOptionWithArg{Option:all,Implied:key}")
+}
+
+func TestParseCmd_constraints(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "json", IsGlobal: true},
+			cliargs.OptCfg{Name: "table", IsGlobal: true},
+		},
+		Constraints: []cliargs.OptConstraint{
+			cliargs.Exclusive("json", "table"),
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{Name: "list"},
+		},
+	}
+
+	_, err := cliargs.ParseCmd(
+		[]string{"app", "--json", "list", "--table"}, cmdCfg)
+	assert.Equal(t, err.Error(), "OptionsAreExclusive{Option:json,Other:table}")
+
+	_, err = cliargs.ParseCmd([]string{"app", "list", "--table"}, cmdCfg)
+	assert.Nil(t, err)
+}
+
+func TestAddConstraints(t *testing.T) {
+	help := cliargs.NewHelp()
+	help.AddConstraints([]cliargs.OptConstraint{
+		cliargs.Exclusive("json", "table", "y"),
+		cliargs.Requires("key", "cert"),
+		cliargs.Implies("a", "recursive", "hidden"),
+	}, 0, 2)
+	iter := help.Iter()
+
+	line, status := iter.Next()
+	assert.Equal(t, line, "  --json | --table | -y (mutually exclusive)")
+	assert.Equal(t, status, cliargs.ITER_HAS_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "  --key requires --cert")
+	assert.Equal(t, status, cliargs.ITER_HAS_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "  -a implies --recursive, --hidden")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+}
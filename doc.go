@@ -112,6 +112,72 @@ The following help text is generated from the above optCfgs.
 	//   --foo-bar, -f     This is description of foo-bar.
 	//   --baz, -z <text>  This is description of baz.
 
+Help#AddUsage method adds a usage synopsis which is generated from the option
+configurations.
+A long usage synopsis is wrapped between the options with a hanging indent.
+
+	help := cliargs.NewHelp()
+	help.AddUsage("app", optCfgs, []string{"<file>..."})
+	help.Print()
+
+	// (stdout)
+	// Usage: app [--foo-bar] [--baz <text>...] <file>...
+
+An option configuration of which IsRequired field is true is a required
+option.
+If a required option is not given and has no Default, ParseWith returns
+MissingRequiredOption error which has the option name and its aliases.
+Help#AddOpts marks a required option with "(required)", and Help#AddUsage
+outputs it without brackets.
+
+The option argument of an option can be limited to the allowed values with
+Choices field of OptCfg.
+If an option argument is not any of them, ParseWith returns
+OptionArgIsNotChoice error which has the option argument and the choices.
+Help#AddOpts and Help#AddUsage show the choices like {json|yaml} if ArgHelp is
+empty, and the completion scripts and CompleteArgs offer them as candidates.
+
+Relationships among options can be configured with OptConstraint(s) and
+WithConstraints.
+Exclusive makes options mutually exclusive, Requires makes an option require
+other options, and Implies makes an option imply other options.
+The implied options are registered before OnParsed handlers are called, and
+ParseWith returns OptionsAreExclusive or OptionRequiresOption error which has
+the names of both options if the constraints are not satisfied.
+Help#AddConstraints method adds their descriptions.
+
+	constraints := []cliargs.OptConstraint{
+	    cliargs.Exclusive("json", "table"),
+	    cliargs.Requires("key", "cert"),
+	    cliargs.Implies("all", "recursive"),
+	}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithConstraints(constraints...))
+
+Command arguments can be configured with an ArgCfg array and WithArgCfgs.
+An ArgCfg is required, optional (IsOptional), or variadic (IsVariadic), and
+ParseWith returns MissingArg or ExtraArg error if the command arguments do not
+match them.
+Help#AddArgs method adds their descriptions, and MakeUsageArgs function makes
+the texts of command arguments for Help#AddUsage method.
+
+	argCfgs := []cliargs.ArgCfg{
+	    cliargs.ArgCfg{Name:"src", Desc:"The source file."},
+	    cliargs.ArgCfg{Name:"dest", IsOptional:true, IsVariadic:true, Desc:"The destinations."},
+	}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithArgCfgs(argCfgs...))
+
+	help := cliargs.NewHelp()
+	help.AddUsage("app", optCfgs, cliargs.MakeUsageArgs(argCfgs))
+	help.AddText("ARGUMENTS:")
+	help.AddArgs(argCfgs, 0, 2)
+	help.Print()
+
+	// (stdout)
+	// Usage: app [--foo-bar] [--baz <text>...] <src> [<dest>...]
+	// ARGUMENTS:
+	//   <src>        The source file.
+	//   [<dest>...]  The destinations.
+
 # Parse for an option store with struct tags
 
 This library provides the function ParseFor which takes a pointer of a struct
@@ -121,7 +187,8 @@ This struct needs to struct tags for its fields.
 This function creates a Cmd instance and also an array of OptCfg which is
 transformed from these struct tags and is used to parse command line arguments.
 
-The struct tags used in a option store struct are optcfg, optdesc, and optarg.
+The struct tags used in a option store struct are optcfg, optdesc, optarg,
+optimplicit, optdecrements, and optchoices.
 optcfg is what to specify option configurations other than Desc and AtgHelp.
 The format of optcfg is as follows:
 
@@ -130,9 +197,18 @@ The format of optcfg is as follows:
 	`optcfg:"name=value"`           // with a default value
 	`optcfg:"name=[value1,value2]`  // with defalt values for array
 	`optcfg:"name=:[value1:value2]` // with default values and separator is :
+	`optcfg:"[no-]name"`            // negatable with --no-name (only for bool)
+	`optcfg:"+name"`                // counts occurrences (only for integers)
+	`optcfg:"!name"`                // required unless it has a default value
 
 optdesc is what to specify a option description.
 And optarg is what to specify a text for an option argument value in help text.
+optimplicit makes an option argument optional, and is what to specify the
+option argument for when the option is given without it, like --color.
+optdecrements makes a boolean option decrement a counter option, like -q for
+-v, and is what to specify the name of the counter option.
+optchoices is what to specify the allowed values of an option argument, which
+are separated by commas, like `optchoices:"json,yaml,table"`.
 
 	// osArgs := []string{"app", "--foo-bar", "hoge", "--baz", "1", "-z=2", "-x", "fuga"}
 
@@ -211,8 +287,55 @@ The following help text is generated from the above optCfgs (without Help#Print
 
 # Parse command line arguments including sub commands
 
-This library provides the function FindFirstArg which returns an index, an argument, an existent flag.
-This function can be used to parse command line arguments including sub commands, as follows:
+This library provides the function ParseCmd which parses command line
+arguments including sub commands with a command configuration: CmdCfg.
+A command configuration has fields: Name, Aliases, OptCfgs, SubCmds, and Desc.
+OptCfgs field is an array of option configurations of the command, and
+SubCmds field is an array of command configurations of the sub commands.
+The options of each command are parsed until the first command argument, and
+the first command argument selects a sub command by its Name or Aliases.
+If no sub command matches it, this function returns UnconfiguredSubCmd error.
+
+	// osArgs := []string{"app", "--verbose", "use", "--version", "1.2", "qux"}
+
+	cmdCfg := cliargs.CmdCfg{
+	    OptCfgs: []cliargs.OptCfg{
+	        cliargs.OptCfg{Name:"verbose"},
+	    },
+	    SubCmds: []cliargs.CmdCfg{
+	        cliargs.CmdCfg{
+	            Name:"list",
+	            OptCfgs: []cliargs.OptCfg{ cliargs.OptCfg{Name:"all"} },
+	        },
+	        cliargs.CmdCfg{
+	            Name:"use",
+	            OptCfgs: []cliargs.OptCfg{
+	                cliargs.OptCfg{Name:"version", HasArg:true},
+	            },
+	        },
+	    },
+	}
+
+	cmd, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	cmd.Name                   // app
+	cmd.HasOpt("verbose")      // true
+	subCmd, exists := cmd.SubCmd()
+	exists                     // true
+	subCmd.Name                // use
+	subCmd.OptArg("version")   // 1.2
+	subCmd.Args()              // [qux]
+
+An option configuration of which IsGlobal field is true is a global option.
+A global option is accepted also after the names of sub commands, and its
+option arguments are registered once to the Cmd of the command where it is
+configured.
+Cmd#HasOpt, Cmd#OptArg, and Cmd#OptArgs of sub commands can obtain them, too.
+Help#AddOpts does not output global options, and Help#AddGlobalOpts outputs
+only global options, so that global options can be shown in their own
+section of a help text.
+
+This library also provides the function FindFirstArg which returns an index, an argument, an existent flag.
+This function can be used to parse command line arguments including sub commands by hand, as follows:
 
 	i, arg, exists := cliargs.FindFirstArg(osArgs)
 	if !exists { return }
@@ -259,5 +382,203 @@ And help text can be generated as follows:
 	// use         The description of use sub-command.
 	//   --baz     The description of baz option.
 	//   ...
+
+# Change behaviors of parsing
+
+ParseWith, ParseFor, and ParseCmd functions can take ParseOpt(s) as variadic
+arguments to change behaviors of parsing.
+All behaviors changed by ParseOpt are opt-in.
+
+WithAttachedShortArgs makes the rest of a short option cluster an option
+argument of a short option which takes an option argument, as POSIX getopt
+does.
+
+	// osArgs := []string{"app", "-vofoo"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithAttachedShortArgs())
+	cmd.HasOpt("v")  // true
+	cmd.OptArg("o")  // foo
+
+WithLongOptAbbrev makes an abbreviated long option, which is a unique prefix
+of the names and aliases of the options, available as GNU getopt_long does.
+If an abbreviated long option is ambiguous, AmbiguousOption error is returned.
+
+	// osArgs := []string{"app", "--verb"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithLongOptAbbrev())
+	cmd.HasOpt("verbose")  // true
+
+WithBoolOptArgs makes an option which takes no option argument accept a
+boolean value attached with "=", like true/false, yes/no, on/off, or 1/0.
+A false value turns the option off as same as a negated option.
+If the value is not a boolean, OptionArgIsNotBool error is returned.
+
+	// osArgs := []string{"app", "--dry-run=false", "--verbose=yes"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithBoolOptArgs())
+	cmd.HasOpt("dry-run")  // false
+	cmd.HasOpt("verbose")  // true
+
+WithNegativeNumbers makes a command line argument which is a negative number,
+like -5, -3.14, or -1e3, a command argument instead of short options.
+This is ignored if a digit is configured as an option name or alias, or if an
+option of which IsNumberOpt field is true is configured.
+
+	// osArgs := []string{"calc", "-5", "3"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithNegativeNumbers())
+	cmd.Args()  // [-5 3]
+
+ParseWith also supports numeric short options.
+A digit which is configured as an option name or alias can be used as a short
+option, like -9 of gzip, and can be combined with other short options.
+And an option of which IsNumberOpt field is true takes a command line argument
+which consists of "-" and digits, like -20 of head, with the digits as its
+option argument.
+
+	optCfgs := []cliargs.OptCfg{
+	    cliargs.OptCfg{Name:"best", Aliases:[]string{"9"}},
+	    cliargs.OptCfg{Name:"lines", Aliases:[]string{"n"}, HasArg:true, IsNumberOpt:true},
+	}
+	// osArgs := []string{"app", "-9", "-20"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs)
+	cmd.HasOpt("best")      // true
+	cmd.OptArg("lines")     // 20
+
+WithStopAtFirstArg stops parsing options at the first command argument, as
+POSIX getopt does with POSIXLY_CORRECT.
+The first command argument and all the following command line arguments are
+taken as command arguments verbatim, so they can be passed to another command.
+
+	// osArgs := []string{"mytool", "-v", "exec", "--", "kubectl", "-n", "foo"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithStopAtFirstArg())
+	cmd.HasOpt("verbose")  // true
+	cmd.Args()             // [exec -- kubectl -n foo]
+
+WithResponseFiles expands a command line argument like @args.txt to the tokens
+in the file before parsing, as javac and gcc do.
+The tokens are separated by white spaces and line breaks, and can be quoted as
+in a shell.
+A backslash escapes the next character outside quotes, so a Windows path needs
+to be quoted with single quotes, like '@C:\dir\args.txt'.
+A response file can include other response files, and @- reads standard input.
+If a response file cannot be expanded, ResponseFileError is returned, which
+has the file path and the line number.
+This ParseOpt is available also for Parse.
+
+	// args.txt:
+	//   --define 'a b'
+	//   src/main.c
+	// osArgs := []string{"app", "@args.txt"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithResponseFiles())
+	cmd.OptArg("define")  // a b
+	cmd.Args()            // [src/main.c]
+
+# Generate shell completion scripts
+
+This library provides the function MakeBashCompletion which generates a bash
+completion script from a command configuration.
+The script completes long and short options, and names of sub commands.
+The option argument of an option is completed according to CompHint field of
+its option configuration: COMP_NONE (offers nothing), COMP_FILE, or COMP_DIR.
+
+	cmdCfg := cliargs.CmdCfg{
+	    Name: "app",
+	    OptCfgs: []cliargs.OptCfg{
+	        cliargs.OptCfg{Name:"verbose", Aliases:[]string{"v"}},
+	        cliargs.OptCfg{Name:"config", HasArg:true, CompHint:cliargs.COMP_FILE},
+	    },
+	}
+	fmt.Print(cliargs.MakeBashCompletion(cmdCfg))
+
+This library also provides the functions MakeZshCompletion and
+MakeFishCompletion which generate a zsh completion script and a fish
+completion script.
+These scripts show the first line of Desc field of each option configuration
+and each command configuration as the description of a candidate.
+
+Candidates which are known only at run time, like names of remote hosts, can be
+offered by OnComplete field of an option configuration or a command
+configuration.
+The scripts generated by the above functions call the command back with the
+hidden sub command "__complete" to obtain such candidates, so the command has
+to call RunCompletion function before parsing command line arguments.
+CompleteFiles, CompleteDirs, and CompleteChoices functions create frequently
+used handlers for OnComplete.
+
+	cmdCfg := cliargs.CmdCfg{
+	    Name: "app",
+	    OptCfgs: []cliargs.OptCfg{
+	        cliargs.OptCfg{
+	            Name: "color",
+	            HasArg: true,
+	            OnComplete: cliargs.CompleteChoices("always", "auto", "never"),
+	        },
+	    },
+	}
+	if cliargs.RunCompletion(os.Args, cmdCfg) {
+	    return
+	}
+	cmd, err := cliargs.ParseCmd(os.Args, cmdCfg)
+
+# Generate a man page
+
+This library provides the function MakeManPage which generates a man page in
+man(7) roff format from a man page configuration and option configurations.
+Because the man page is generated from the same option configurations that are
+used for parsing, the man page does not drift from the options which the
+command accepts.
+It is convenient to run this function from go generate.
+
+	manCfg := cliargs.ManPageCfg{
+	    Name: "app",
+	    Summary: "does something",
+	    Synopsis: []string{"app [OPTIONS] <file>..."},
+	    Description: "This command does something.",
+	}
+	os.WriteFile("app.1", []byte(cliargs.MakeManPage(manCfg, optCfgs)), 0644)
+
+This library also provides the functions MakeMarkdownReference and
+MakeHTMLReference which generate a reference document of a command and its sub
+commands in Markdown and in HTML from a command configuration.
+The document has the tables of options which show the names, aliases,
+ArgHelp, Default, and Desc of the options as same as the help text.
+
+# Export option configurations as JSON
+
+OptCfg can be converted to and from JSON with encoding/json package, and
+MarshalOptCfgs and UnmarshalOptCfgs functions convert an array of option
+configurations.
+This JSON representation is for tools which read the option specification of a
+command without running Go.
+OnParsed and OnComplete fields are not included in the JSON representation
+because functions cannot be serialized, so they need to be set again after
+loading.
+
+	data, err := cliargs.MarshalOptCfgs(optCfgs)
+	// [
+	//   {
+	//     "name": "baz",
+	//     "aliases": [
+	//       "z"
+	//     ],
+	//     "hasArg": true,
+	//     "isArray": true,
+	//     "default": [
+	//       "9",
+	//       "8",
+	//       "7"
+	//     ],
+	//     "desc": "This is description of baz.",
+	//     "argHelp": "<text>"
+	//   }
+	// ]
+
+This library also provides LoadOptCfgs and LoadCmdCfg functions which read
+option configurations and a command configuration from a spec file written in
+YAML or JSON.
+If the spec file is malformed, these functions return a SpecError which has the
+line, the column, and the path of the offending key.
+
+	f, _ := os.Open("options.yml")
+	defer f.Close()
+	optCfgs, err := cliargs.LoadOptCfgs(f)
+	cmd, err := cliargs.ParseWith(os.Args, optCfgs)
 */
 package cliargs
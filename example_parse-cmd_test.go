@@ -0,0 +1,54 @@
+package cliargs_test
+
+import (
+	"fmt"
+
+	"github.com/sttk/cliargs"
+)
+
+func ExampleParseCmd() {
+	osArgs := []string{
+		"path/to/app", "--verbose", "use", "--version", "1.2", "qux",
+	}
+	cmdCfg := cliargs.CmdCfg{
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name: "list",
+				OptCfgs: []cliargs.OptCfg{
+					cliargs.OptCfg{Name: "all"},
+				},
+			},
+			cliargs.CmdCfg{
+				Name: "use",
+				OptCfgs: []cliargs.OptCfg{
+					cliargs.OptCfg{Name: "version", HasArg: true},
+				},
+			},
+		},
+	}
+
+	cmd, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	fmt.Printf("err = %v\n", err)
+	fmt.Printf("cmd.Name = %v\n", cmd.Name)
+	fmt.Printf("cmd.HasOpt(\"verbose\") = %v\n", cmd.HasOpt("verbose"))
+	fmt.Printf("cmd.Args() = %v\n", cmd.Args())
+
+	subCmd, exists := cmd.SubCmd()
+	fmt.Printf("exists = %v\n", exists)
+	fmt.Printf("subCmd.Name = %v\n", subCmd.Name)
+	fmt.Printf("subCmd.OptArg(\"version\") = %v\n", subCmd.OptArg("version"))
+	fmt.Printf("subCmd.Args() = %v\n", subCmd.Args())
+
+	// Output:
+	// err = <nil>
+	// cmd.Name = app
+	// cmd.HasOpt("verbose") = true
+	// cmd.Args() = []
+	// exists = true
+	// subCmd.Name = use
+	// subCmd.OptArg("version") = 1.2
+	// subCmd.Args() = [qux]
+}
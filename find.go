@@ -12,6 +12,10 @@ import (
 // of first non option-format element in a specified string array.
 // If non option-format element is found, a existent flag is true, but if
 // the element is not found, the flag is false.
+//
+// To parse options until the first command argument and to take the rest of
+// command line arguments verbatim, WithStopAtFirstArg can be used with
+// ParseWith or ParseFor instead of this function.
 func FindFirstArg(osArgs []string) (index int, arg string, exists bool) {
 	isNonOpt := false
 	if len(osArgs) > 0 {
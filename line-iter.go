@@ -38,6 +38,7 @@ type lineIter struct {
 	lboPos  int
 	limit   int
 	indent  string
+	lbo     func(rune) lboType
 }
 
 func newLineIter(text string, lineWidth int) lineIter {
@@ -48,6 +49,7 @@ func newLineIter(text string, lineWidth int) lineIter {
 	iter.scanner = sc
 	iter.buffer = newRuneBuffer(lineWidth)
 	iter.limit = lineWidth
+	iter.lbo = lineBreakOppotunity
 	return iter
 }
 
@@ -69,7 +71,7 @@ func (iter *lineIter) Next() (string, IterStatus) {
 	var line string
 
 	for r := iter.scanner.Next(); r != scanner.EOF; r = iter.scanner.Next() {
-		lboTyp := lineBreakOppotunity(r)
+		lboTyp := iter.lbo(r)
 
 		if lboTyp == lbo_break {
 			line = string(iter.buffer.slice())
@@ -171,7 +173,28 @@ func lineBreakOppotunity(r rune) lboType {
 	return lbo_never
 }
 
+// usageSpace is a rune in the private use area which is used instead of a
+// space in an item of a usage synopsis so that the item is not broken.
+// This rune is replaced with a space when the usage synopsis is output.
+const usageSpace = '\ue000'
+
+// spaceLineBreakOppotunity is a function which allows line breaks only at
+// spaces, and is used for texts which must not be broken at punctuations, like
+// usage synopses.
+func spaceLineBreakOppotunity(r rune) lboType {
+	if r == 0x0a || r == 0x0d {
+		return lbo_break
+	}
+	if r == ' ' {
+		return lbo_space
+	}
+	return lbo_never
+}
+
 func runeWidth(r rune) int {
+	if r == usageSpace || r == '\u00a0' {
+		return 1
+	}
 	if !unicode.IsPrint(r) {
 		return 0
 	}
@@ -359,3 +359,31 @@ func TestLineIter_textContainsNonPrintChar(t *testing.T) {
 	assert.Equal(t, line, "klmn")
 	assert.Equal(t, status, ITER_NO_MORE)
 }
+
+func TestLineIter_textContainsUsageSpace(t *testing.T) {
+	text := "abc def\ue000ghi"
+	iter := newLineIter(text, 10)
+	iter.lbo = spaceLineBreakOppotunity
+
+	line, status := iter.Next()
+	assert.Equal(t, line, "abc ")
+	assert.Equal(t, status, ITER_HAS_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "def\ue000ghi")
+	assert.Equal(t, status, ITER_NO_MORE)
+}
+
+func TestLineIter_spaceLineBreakOppotunity(t *testing.T) {
+	text := "[--abc-def] [--ghi-jkl]"
+	iter := newLineIter(text, 16)
+	iter.lbo = spaceLineBreakOppotunity
+
+	line, status := iter.Next()
+	assert.Equal(t, line, "[--abc-def] ")
+	assert.Equal(t, status, ITER_HAS_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "[--ghi-jkl]")
+	assert.Equal(t, status, ITER_NO_MORE)
+}
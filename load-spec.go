@@ -0,0 +1,435 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargs
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SpecError is an error which indicates that a spec file read by LoadOptCfgs
+// or LoadCmdCfg is malformed.
+// Line and Column are the location in the spec file, and Path is the path to
+// the offending key, like [1].hasArg or subCmds[0].options[2].
+// Err is the error which indicates the cause, and can be taken with
+// errors.Unwrap, errors.Is, or errors.As.
+type SpecError struct {
+	Line, Column int
+	Path         string
+	Err          error
+}
+
+func (e SpecError) Error() string {
+	return fmt.Sprintf("SpecError{Line:%d,Column:%d,Path:%s,Err:%s}",
+		e.Line, e.Column, e.Path, e.Err.Error())
+}
+
+// Unwrap is a method which returns the error which indicates the cause.
+func (e SpecError) Unwrap() error {
+	return e.Err
+}
+
+// UnknownSpecKey is an error which indicates that a key in a spec file is
+// unknown.
+type UnknownSpecKey struct{ Key string }
+
+func (e UnknownSpecKey) Error() string {
+	return fmt.Sprintf("UnknownSpecKey{Key:%s}", e.Key)
+}
+
+// SpecKeyIsMissing is an error which indicates that a required key is missing
+// in a spec file.
+type SpecKeyIsMissing struct{ Key string }
+
+func (e SpecKeyIsMissing) Error() string {
+	return fmt.Sprintf("SpecKeyIsMissing{Key:%s}", e.Key)
+}
+
+// SpecTypeMismatch is an error which indicates that the type of a value in a
+// spec file is different from the expected type.
+type SpecTypeMismatch struct{ Key, Expected string }
+
+func (e SpecTypeMismatch) Error() string {
+	return fmt.Sprintf("SpecTypeMismatch{Key:%s,Expected:%s}",
+		e.Key, e.Expected)
+}
+
+// LoadOptCfgs is a function which reads option configurations from a spec
+// file written in YAML or JSON.
+// The spec file is an array of option configurations, and each option
+// configuration is a mapping of which keys are same as the JSON representation
+// of OptCfg: "name", "aliases", "hasArg", "isArray", "default", "desc",
+// "argHelp", "isGlobal", "compHint", "isNegatable", "isCounter", "decrements",
+// "isArgOptional", "implicitArg", "arity", "isNumberOpt", "isRequired", and
+// "choices".
+// (See OptCfg#MarshalJSON method.)
+// "name" is required.
+//
+//	# options.yml
+//	- name: foo-bar
+//	  aliases: [f]
+//	  desc: This is description of foo-bar.
+//	- name: baz
+//	  hasArg: true
+//	  isArray: true
+//	  default: ["9", "8", "7"]
+//	  argHelp: <num>
+//
+// If the spec file is malformed, this function returns a SpecError which has
+// the location and the path of the offending key.
+// The cause of a SpecError is UnknownSpecKey, SpecKeyIsMissing,
+// SpecTypeMismatch, UnknownCompHint, ConfigIsArrayButHasNoArg,
+// ConfigHasDefaultButHasNoArg, ConfigIsNegatableButHasArg,
+// ConfigIsCounterButHasArg, ConfigIsArgOptionalButHasNoArg,
+// ConfigHasInvalidArity, ConfigIsNumberOptButHasNoArg,
+// ConfigHasChoicesButHasNoArg, or a syntax error of YAML.
+func LoadOptCfgs(r io.Reader) ([]OptCfg, error) {
+	node, err := readSpec(r)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return []OptCfg{}, nil
+	}
+	return decodeOptCfgs(node, "")
+}
+
+// LoadCmdCfg is a function which reads a command configuration from a spec
+// file written in YAML or JSON.
+// The spec file is a mapping which has the keys: "name", "aliases", "desc",
+// "options", "args", and "subCmds".
+// "options" is an array of option configurations in the same format as the
+// spec file for LoadOptCfgs, "args" is an array of command argument
+// configurations which are mappings of which keys are "name", "isOptional",
+// "isVariadic", and "desc", and "subCmds" is an array of command
+// configurations in the same format as this spec file.
+// "name" is required for sub commands and command arguments.
+//
+//	name: app
+//	options:
+//	  - name: verbose
+//	    aliases: [v]
+//	    isGlobal: true
+//	subCmds:
+//	  - name: list
+//	    aliases: [ls]
+//	    desc: Lists items.
+//	    args:
+//	      - name: pattern
+//	        isOptional: true
+//
+// If the spec file is malformed, this function returns a SpecError as same as
+// LoadOptCfgs function.
+// The cause of a SpecError can also be ConfigArgIsMisplaced.
+func LoadCmdCfg(r io.Reader) (CmdCfg, error) {
+	node, err := readSpec(r)
+	if err != nil {
+		return CmdCfg{}, err
+	}
+	if node == nil {
+		return CmdCfg{}, nil
+	}
+	return decodeCmdCfg(node, "", false)
+}
+
+func readSpec(r io.Reader) (*yaml.Node, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	err = yaml.Unmarshal(data, &doc)
+	if err != nil {
+		line := 0
+		fmt.Sscanf(err.Error(), "yaml: line %d:", &line)
+		return nil, SpecError{Line: line, Err: err}
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	return doc.Content[0], nil
+}
+
+func specError(node *yaml.Node, path string, err error) SpecError {
+	return SpecError{Line: node.Line, Column: node.Column, Path: path, Err: err}
+}
+
+func specPath(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
+
+func specIndexPath(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+func decodeCmdCfg(node *yaml.Node, path string, isSub bool) (CmdCfg, error) {
+	var cmdCfg CmdCfg
+
+	if node.Kind != yaml.MappingNode {
+		return cmdCfg, specError(node, path, SpecTypeMismatch{
+			Key: path, Expected: "mapping"})
+	}
+
+	hasName := false
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k, v := node.Content[i], node.Content[i+1]
+		p := specPath(path, k.Value)
+		var err error
+
+		switch k.Value {
+		case "name":
+			cmdCfg.Name, err = decodeSpecString(v, p, k.Value)
+			hasName = true
+		case "aliases":
+			cmdCfg.Aliases, err = decodeSpecStrings(v, p, k.Value)
+		case "desc":
+			cmdCfg.Desc, err = decodeSpecString(v, p, k.Value)
+		case "options":
+			cmdCfg.OptCfgs, err = decodeOptCfgs(v, p)
+		case "args":
+			cmdCfg.ArgCfgs, err = decodeArgCfgs(v, p)
+		case "subCmds":
+			if v.Kind != yaml.SequenceNode {
+				err = specError(v, p, SpecTypeMismatch{
+					Key: k.Value, Expected: "array"})
+				break
+			}
+			cmdCfg.SubCmds = make([]CmdCfg, len(v.Content))
+			for j, c := range v.Content {
+				cmdCfg.SubCmds[j], err = decodeCmdCfg(c, specIndexPath(p, j), true)
+				if err != nil {
+					break
+				}
+			}
+		default:
+			err = specError(k, p, UnknownSpecKey{Key: k.Value})
+		}
+
+		if err != nil {
+			return cmdCfg, err
+		}
+	}
+
+	if isSub && !hasName {
+		return cmdCfg, specError(node, path, SpecKeyIsMissing{Key: "name"})
+	}
+	return cmdCfg, nil
+}
+
+func decodeOptCfgs(node *yaml.Node, path string) ([]OptCfg, error) {
+	if node.Kind != yaml.SequenceNode {
+		return nil, specError(node, path, SpecTypeMismatch{
+			Key: path, Expected: "array"})
+	}
+
+	optCfgs := make([]OptCfg, len(node.Content))
+	for i, c := range node.Content {
+		cfg, err := decodeOptCfg(c, specIndexPath(path, i))
+		if err != nil {
+			return nil, err
+		}
+		optCfgs[i] = cfg
+	}
+	return optCfgs, nil
+}
+
+func decodeOptCfg(node *yaml.Node, path string) (OptCfg, error) {
+	var cfg OptCfg
+
+	if node.Kind != yaml.MappingNode {
+		return cfg, specError(node, path, SpecTypeMismatch{
+			Key: path, Expected: "mapping"})
+	}
+
+	hasName := false
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k, v := node.Content[i], node.Content[i+1]
+		p := specPath(path, k.Value)
+		var err error
+
+		switch k.Value {
+		case "name":
+			cfg.Name, err = decodeSpecString(v, p, k.Value)
+			hasName = true
+		case "aliases":
+			cfg.Aliases, err = decodeSpecStrings(v, p, k.Value)
+		case "hasArg":
+			cfg.HasArg, err = decodeSpecBool(v, p, k.Value)
+		case "isArray":
+			cfg.IsArray, err = decodeSpecBool(v, p, k.Value)
+		case "default":
+			cfg.Default, err = decodeSpecStrings(v, p, k.Value)
+		case "desc":
+			cfg.Desc, err = decodeSpecString(v, p, k.Value)
+		case "argHelp":
+			cfg.ArgHelp, err = decodeSpecString(v, p, k.Value)
+		case "isGlobal":
+			cfg.IsGlobal, err = decodeSpecBool(v, p, k.Value)
+		case "compHint":
+			var s string
+			s, err = decodeSpecString(v, p, k.Value)
+			if err == nil && len(s) > 0 {
+				var ok bool
+				cfg.CompHint, ok = parseCompHint(s)
+				if !ok {
+					err = specError(v, p, UnknownCompHint{Option: cfg.Name, CompHint: s})
+				}
+			}
+		case "isNegatable":
+			cfg.IsNegatable, err = decodeSpecBool(v, p, k.Value)
+		case "isCounter":
+			cfg.IsCounter, err = decodeSpecBool(v, p, k.Value)
+		case "decrements":
+			cfg.Decrements, err = decodeSpecString(v, p, k.Value)
+		case "isArgOptional":
+			cfg.IsArgOptional, err = decodeSpecBool(v, p, k.Value)
+		case "implicitArg":
+			cfg.ImplicitArg, err = decodeSpecString(v, p, k.Value)
+		case "arity":
+			cfg.Arity, err = decodeSpecInt(v, p, k.Value)
+		case "isNumberOpt":
+			cfg.IsNumberOpt, err = decodeSpecBool(v, p, k.Value)
+		case "isRequired":
+			cfg.IsRequired, err = decodeSpecBool(v, p, k.Value)
+		case "choices":
+			cfg.Choices, err = decodeSpecStrings(v, p, k.Value)
+		default:
+			err = specError(k, p, UnknownSpecKey{Key: k.Value})
+		}
+
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	if !hasName {
+		return cfg, specError(node, path, SpecKeyIsMissing{Key: "name"})
+	}
+
+	err := checkOptCfg(cfg)
+	if err != nil {
+		return cfg, specError(node, path, err)
+	}
+	return cfg, nil
+}
+
+func decodeArgCfgs(node *yaml.Node, path string) ([]ArgCfg, error) {
+	if node.Kind != yaml.SequenceNode {
+		return nil, specError(node, path, SpecTypeMismatch{
+			Key: path, Expected: "array"})
+	}
+
+	argCfgs := make([]ArgCfg, len(node.Content))
+	for i, c := range node.Content {
+		cfg, err := decodeArgCfg(c, specIndexPath(path, i))
+		if err != nil {
+			return nil, err
+		}
+		argCfgs[i] = cfg
+	}
+
+	err := checkArgCfgs(argCfgs)
+	if err != nil {
+		return nil, specError(node, path, err)
+	}
+	return argCfgs, nil
+}
+
+func decodeArgCfg(node *yaml.Node, path string) (ArgCfg, error) {
+	var cfg ArgCfg
+
+	if node.Kind != yaml.MappingNode {
+		return cfg, specError(node, path, SpecTypeMismatch{
+			Key: path, Expected: "mapping"})
+	}
+
+	hasName := false
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k, v := node.Content[i], node.Content[i+1]
+		p := specPath(path, k.Value)
+		var err error
+
+		switch k.Value {
+		case "name":
+			cfg.Name, err = decodeSpecString(v, p, k.Value)
+			hasName = true
+		case "isOptional":
+			cfg.IsOptional, err = decodeSpecBool(v, p, k.Value)
+		case "isVariadic":
+			cfg.IsVariadic, err = decodeSpecBool(v, p, k.Value)
+		case "desc":
+			cfg.Desc, err = decodeSpecString(v, p, k.Value)
+		default:
+			err = specError(k, p, UnknownSpecKey{Key: k.Value})
+		}
+
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	if !hasName {
+		return cfg, specError(node, path, SpecKeyIsMissing{Key: "name"})
+	}
+	return cfg, nil
+}
+
+func isSpecNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+func decodeSpecString(node *yaml.Node, path, key string) (string, error) {
+	if node.Kind != yaml.ScalarNode || isSpecNull(node) {
+		return "", specError(node, path, SpecTypeMismatch{
+			Key: key, Expected: "string"})
+	}
+	return node.Value, nil
+}
+
+func decodeSpecStrings(node *yaml.Node, path, key string) ([]string, error) {
+	if isSpecNull(node) {
+		return nil, nil
+	}
+	if node.Kind != yaml.SequenceNode {
+		return nil, specError(node, path, SpecTypeMismatch{
+			Key: key, Expected: "array of strings"})
+	}
+	arr := make([]string, len(node.Content))
+	for i, c := range node.Content {
+		if c.Kind != yaml.ScalarNode || isSpecNull(c) {
+			return nil, specError(c, specIndexPath(path, i), SpecTypeMismatch{
+				Key: key, Expected: "array of strings"})
+		}
+		arr[i] = c.Value
+	}
+	return arr, nil
+}
+
+func decodeSpecBool(node *yaml.Node, path, key string) (bool, error) {
+	var b bool
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" ||
+		node.Decode(&b) != nil {
+		return false, specError(node, path, SpecTypeMismatch{
+			Key: key, Expected: "bool"})
+	}
+	return b, nil
+}
+
+func decodeSpecInt(node *yaml.Node, path, key string) (int, error) {
+	var n int
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!int" ||
+		node.Decode(&n) != nil {
+		return 0, specError(node, path, SpecTypeMismatch{
+			Key: key, Expected: "int"})
+	}
+	return n, nil
+}
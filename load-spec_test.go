@@ -0,0 +1,279 @@
+package cliargs_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sttk/cliargs"
+)
+
+func TestLoadOptCfgs_yaml(t *testing.T) {
+	spec := `
+- name: foo-bar
+  aliases: [f]
+  desc: This is description of foo-bar.
+- name: baz
+  hasArg: true
+  isArray: true
+  default: ["9", "8", "7"]
+  argHelp: <num>
+  isGlobal: true
+  compHint: file
+- name: qux
+  hasArg: true
+  default: []
+`
+	optCfgs, err := cliargs.LoadOptCfgs(strings.NewReader(spec))
+	assert.Nil(t, err)
+	assert.Equal(t, len(optCfgs), 3)
+
+	assert.Equal(t, optCfgs[0].Name, "foo-bar")
+	assert.Equal(t, optCfgs[0].Aliases, []string{"f"})
+	assert.False(t, optCfgs[0].HasArg)
+	assert.Nil(t, optCfgs[0].Default)
+	assert.Equal(t, optCfgs[0].Desc, "This is description of foo-bar.")
+
+	assert.Equal(t, optCfgs[1].Name, "baz")
+	assert.True(t, optCfgs[1].HasArg)
+	assert.True(t, optCfgs[1].IsArray)
+	assert.Equal(t, optCfgs[1].Default, []string{"9", "8", "7"})
+	assert.Equal(t, optCfgs[1].ArgHelp, "<num>")
+	assert.True(t, optCfgs[1].IsGlobal)
+	assert.Equal(t, optCfgs[1].CompHint, cliargs.COMP_FILE)
+
+	assert.Equal(t, optCfgs[2].Name, "qux")
+	assert.NotNil(t, optCfgs[2].Default)
+	assert.Equal(t, len(optCfgs[2].Default), 0)
+
+	cmd, err := cliargs.ParseWith([]string{"app", "-f", "--baz", "1"}, optCfgs)
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("foo-bar"))
+	assert.Equal(t, cmd.OptArgs("baz"), []string{"1"})
+}
+
+func TestLoadOptCfgs_json(t *testing.T) {
+	optCfgs0 := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo", Aliases: []string{"f"}},
+		cliargs.OptCfg{Name: "bar", HasArg: true, Default: []string{"x"},
+			CompHint: cliargs.COMP_DIR},
+	}
+	data, err := cliargs.MarshalOptCfgs(optCfgs0)
+	assert.Nil(t, err)
+
+	optCfgs, err := cliargs.LoadOptCfgs(strings.NewReader(string(data)))
+	assert.Nil(t, err)
+	assert.Equal(t, optCfgs, optCfgs0)
+}
+
+func TestLoadOptCfgs_empty(t *testing.T) {
+	optCfgs, err := cliargs.LoadOptCfgs(strings.NewReader(""))
+	assert.Nil(t, err)
+	assert.Equal(t, optCfgs, []cliargs.OptCfg{})
+}
+
+func TestLoadOptCfgs_syntaxError(t *testing.T) {
+	spec := "- name: foo\n  desc: [abc\n"
+	optCfgs, err := cliargs.LoadOptCfgs(strings.NewReader(spec))
+	assert.Nil(t, optCfgs)
+	switch e := err.(type) {
+	case cliargs.SpecError:
+		assert.Equal(t, e.Line, 1)
+		assert.NotNil(t, e.Err)
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestLoadOptCfgs_unknownKey(t *testing.T) {
+	spec := "- name: foo\n- name: bar\n  hasArgs: true\n"
+	optCfgs, err := cliargs.LoadOptCfgs(strings.NewReader(spec))
+	assert.Nil(t, optCfgs)
+	assert.Equal(t, err.Error(),
+		"SpecError{Line:3,Column:3,Path:[1].hasArgs,Err:UnknownSpecKey{Key:hasArgs}}")
+	var e cliargs.UnknownSpecKey
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, e.Key, "hasArgs")
+}
+
+func TestLoadOptCfgs_typeMismatch(t *testing.T) {
+	spec := `[{"name": "foo", "hasArg": "yes"}]`
+	_, err := cliargs.LoadOptCfgs(strings.NewReader(spec))
+	assert.Equal(t, err.Error(),
+		"SpecError{Line:1,Column:28,Path:[0].hasArg,Err:SpecTypeMismatch{Key:hasArg,Expected:bool}}")
+
+	spec = "- name: foo\n  hasArg: true\n  default: [a, [b]]\n"
+	_, err = cliargs.LoadOptCfgs(strings.NewReader(spec))
+	assert.Equal(t, err.Error(),
+		"SpecError{Line:3,Column:16,Path:[0].default[1],Err:SpecTypeMismatch{Key:default,Expected:array of strings}}")
+
+	spec = "name: foo\n"
+	_, err = cliargs.LoadOptCfgs(strings.NewReader(spec))
+	assert.Equal(t, err.Error(),
+		"SpecError{Line:1,Column:1,Path:,Err:SpecTypeMismatch{Key:,Expected:array}}")
+}
+
+func TestLoadOptCfgs_nameIsMissing(t *testing.T) {
+	spec := "- name: foo\n- desc: bar\n"
+	_, err := cliargs.LoadOptCfgs(strings.NewReader(spec))
+	assert.Equal(t, err.Error(),
+		"SpecError{Line:2,Column:3,Path:[1],Err:SpecKeyIsMissing{Key:name}}")
+}
+
+func TestLoadOptCfgs_unknownCompHint(t *testing.T) {
+	spec := "- name: foo\n  hasArg: true\n  compHint: host\n"
+	_, err := cliargs.LoadOptCfgs(strings.NewReader(spec))
+	assert.Equal(t, err.Error(),
+		"SpecError{Line:3,Column:13,Path:[0].compHint,Err:UnknownCompHint{Option:foo,CompHint:host}}")
+}
+
+func TestLoadOptCfgs_configIsArrayButHasNoArg(t *testing.T) {
+	spec := "- name: foo\n  isArray: true\n"
+	_, err := cliargs.LoadOptCfgs(strings.NewReader(spec))
+	assert.Equal(t, err.Error(),
+		"SpecError{Line:1,Column:3,Path:[0],Err:ConfigIsArrayButHasNoArg{Option:foo}}")
+	var e cliargs.ConfigIsArrayButHasNoArg
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, e.Option, "foo")
+}
+
+func TestLoadOptCfgs_configHasDefaultButHasNoArg(t *testing.T) {
+	spec := "- name: foo\n  default: [a]\n"
+	_, err := cliargs.LoadOptCfgs(strings.NewReader(spec))
+	assert.Equal(t, err.Error(),
+		"SpecError{Line:1,Column:3,Path:[0],Err:ConfigHasDefaultButHasNoArg{Option:foo}}")
+	var e cliargs.ConfigHasDefaultButHasNoArg
+	assert.True(t, errors.As(err, &e))
+}
+
+func TestLoadOptCfgs_configIsNegatableButHasArg(t *testing.T) {
+	spec := "- name: foo\n  hasArg: true\n  isNegatable: true\n"
+	_, err := cliargs.LoadOptCfgs(strings.NewReader(spec))
+	assert.Equal(t, err.Error(),
+		"SpecError{Line:1,Column:3,Path:[0],Err:ConfigIsNegatableButHasArg{Option:foo}}")
+	var e cliargs.ConfigIsNegatableButHasArg
+	assert.True(t, errors.As(err, &e))
+}
+
+func TestLoadOptCfgs_arity(t *testing.T) {
+	spec := "- name: point\n  hasArg: true\n  arity: 2\n"
+	optCfgs, err := cliargs.LoadOptCfgs(strings.NewReader(spec))
+	assert.Nil(t, err)
+	assert.Equal(t, optCfgs[0].Arity, 2)
+
+	spec = "- name: point\n  hasArg: true\n  arity: two\n"
+	_, err = cliargs.LoadOptCfgs(strings.NewReader(spec))
+	assert.Equal(t, err.Error(),
+		"SpecError{Line:3,Column:10,Path:[0].arity,Err:SpecTypeMismatch{Key:arity,Expected:int}}")
+}
+
+func TestLoadOptCfgs_isNumberOpt(t *testing.T) {
+	spec := "- name: lines\n  hasArg: true\n  isNumberOpt: true\n"
+	optCfgs, err := cliargs.LoadOptCfgs(strings.NewReader(spec))
+	assert.Nil(t, err)
+	assert.True(t, optCfgs[0].IsNumberOpt)
+
+	spec = "- name: lines\n  isNumberOpt: true\n"
+	_, err = cliargs.LoadOptCfgs(strings.NewReader(spec))
+	var e cliargs.ConfigIsNumberOptButHasNoArg
+	assert.True(t, errors.As(err, &e))
+}
+
+func TestLoadOptCfgs_choices(t *testing.T) {
+	spec := "- name: format\n  hasArg: true\n  choices: [json, yaml]\n"
+	optCfgs, err := cliargs.LoadOptCfgs(strings.NewReader(spec))
+	assert.Nil(t, err)
+	assert.Equal(t, optCfgs[0].Choices, []string{"json", "yaml"})
+
+	spec = "- name: format\n  choices: [json]\n"
+	_, err = cliargs.LoadOptCfgs(strings.NewReader(spec))
+	var e cliargs.ConfigHasChoicesButHasNoArg
+	assert.True(t, errors.As(err, &e))
+}
+
+func TestLoadCmdCfg(t *testing.T) {
+	spec := `
+name: app
+desc: This is an app.
+options:
+  - name: verbose
+    aliases: [v]
+    isGlobal: true
+subCmds:
+  - name: list
+    aliases: [ls]
+    desc: Lists items.
+    options:
+      - name: all
+        aliases: [a]
+  - name: remote
+    subCmds:
+      - name: add
+`
+	cmdCfg, err := cliargs.LoadCmdCfg(strings.NewReader(spec))
+	assert.Nil(t, err)
+	assert.Equal(t, cmdCfg.Name, "app")
+	assert.Equal(t, cmdCfg.Desc, "This is an app.")
+	assert.Equal(t, len(cmdCfg.OptCfgs), 1)
+	assert.True(t, cmdCfg.OptCfgs[0].IsGlobal)
+	assert.Equal(t, len(cmdCfg.SubCmds), 2)
+	assert.Equal(t, cmdCfg.SubCmds[0].Name, "list")
+	assert.Equal(t, cmdCfg.SubCmds[0].Aliases, []string{"ls"})
+	assert.Equal(t, cmdCfg.SubCmds[0].OptCfgs[0].Name, "all")
+	assert.Equal(t, cmdCfg.SubCmds[1].SubCmds[0].Name, "add")
+
+	cmd, err := cliargs.ParseCmd([]string{"app", "ls", "-a", "-v"}, cmdCfg)
+	assert.Nil(t, err)
+	subCmd, ok := cmd.SubCmd()
+	assert.True(t, ok)
+	assert.Equal(t, subCmd.Name, "list")
+	assert.True(t, subCmd.HasOpt("all"))
+	assert.True(t, subCmd.HasOpt("verbose"))
+}
+
+func TestLoadCmdCfg_error(t *testing.T) {
+	spec := "name: app\nsubCmds:\n  - name: list\n    options:\n      - name: a\n        argHelp: [x]\n"
+	_, err := cliargs.LoadCmdCfg(strings.NewReader(spec))
+	assert.Equal(t, err.Error(),
+		"SpecError{Line:6,Column:18,Path:subCmds[0].options[0].argHelp,Err:SpecTypeMismatch{Key:argHelp,Expected:string}}")
+
+	spec = "name: app\nsubCmds:\n  - desc: no name\n"
+	_, err = cliargs.LoadCmdCfg(strings.NewReader(spec))
+	assert.Equal(t, err.Error(),
+		"SpecError{Line:3,Column:5,Path:subCmds[0],Err:SpecKeyIsMissing{Key:name}}")
+
+	spec = "name: app\ncommands: []\n"
+	_, err = cliargs.LoadCmdCfg(strings.NewReader(spec))
+	assert.Equal(t, err.Error(),
+		"SpecError{Line:2,Column:1,Path:commands,Err:UnknownSpecKey{Key:commands}}")
+}
+
+func TestLoadCmdCfg_args(t *testing.T) {
+	spec := `
+name: app
+args:
+  - name: src
+    desc: The source file.
+  - name: dest
+    isOptional: true
+    isVariadic: true
+`
+	cmdCfg, err := cliargs.LoadCmdCfg(strings.NewReader(spec))
+	assert.Nil(t, err)
+	assert.Equal(t, cmdCfg.ArgCfgs, []cliargs.ArgCfg{
+		cliargs.ArgCfg{Name: "src", Desc: "The source file."},
+		cliargs.ArgCfg{Name: "dest", IsOptional: true, IsVariadic: true},
+	})
+
+	spec = "name: app\nargs:\n  - name: a\n    isOptional: true\n  - name: b\n"
+	_, err = cliargs.LoadCmdCfg(strings.NewReader(spec))
+	assert.Equal(t, err.Error(),
+		"SpecError{Line:3,Column:3,Path:args,Err:ConfigArgIsMisplaced{Arg:b}}")
+
+	spec = "name: app\nargs:\n  - desc: no name\n"
+	_, err = cliargs.LoadCmdCfg(strings.NewReader(spec))
+	assert.Equal(t, err.Error(),
+		"SpecError{Line:3,Column:5,Path:args[0],Err:SpecKeyIsMissing{Key:name}}")
+}
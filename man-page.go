@@ -0,0 +1,190 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargs
+
+import (
+	"strings"
+)
+
+// ManPageCfg is a structure that represents a man page configuration.
+// A man page configuration consists of fields: Name, Section, Date, Source,
+// Manual, Summary, Synopsis, Description, and Sections.
+//
+// Name is the command name, and Section is the section number of the manual.
+// If Section is empty, "1" is used.
+//
+// Date, Source, and Manual are shown in the header and the footer of the man
+// page.
+// They are the last modified date, the source of the command (e.g. the package
+// name and its version), and the title of the manual.
+//
+// Summary is the one line description shown in NAME section.
+//
+// Synopsis is the array of usage lines shown in SYNOPSIS section.
+// If the first word of a line is the command name, it is shown in bold.
+// If Synopsis is empty, only the command name is shown.
+//
+// Description is the text of DESCRIPTION section.
+// Paragraphs in Description and in Text of Sections are separated with empty
+// lines.
+//
+// Sections is the array of extra sections which are put after OPTIONS section.
+type ManPageCfg struct {
+	Name        string
+	Section     string
+	Date        string
+	Source      string
+	Manual      string
+	Summary     string
+	Synopsis    []string
+	Description string
+	Sections    []ManSection
+}
+
+// ManSection is a structure that represents an extra section of a man page.
+type ManSection struct {
+	Title string
+	Text  string
+}
+
+// MakeManPage is a function which generates a man page in man(7) roff format
+// from a man page configuration and option configurations.
+//
+// The generated man page consists of NAME, SYNOPSIS, DESCRIPTION, OPTIONS,
+// GLOBAL OPTIONS, and the extra sections.
+// OPTIONS section lists the options of which IsGlobal is false, and GLOBAL
+// OPTIONS section lists the options of which IsGlobal is true.
+// Each option is shown with its names, aliases, ArgHelp, and Desc.
+// DESCRIPTION, OPTIONS, and GLOBAL OPTIONS sections are omitted if they have
+// no content.
+//
+// All texts are escaped, so hyphens and backslashes in them are output as is.
+func MakeManPage(cfg ManPageCfg, optCfgs []OptCfg) string {
+	section := cfg.Section
+	if len(section) == 0 {
+		section = "1"
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(".\\\" This man page is generated by github.com/sttk/cliargs.\n")
+	sb.WriteString(".TH " + roffQuote(strings.ToUpper(cfg.Name)) + " " +
+		roffQuote(section) + " " + roffQuote(cfg.Date) + " " +
+		roffQuote(cfg.Source) + " " + roffQuote(cfg.Manual) + "\n")
+
+	sb.WriteString(".SH NAME\n")
+	if len(cfg.Summary) > 0 {
+		sb.WriteString(roffLine(roffEscape(cfg.Name) + " \\- " +
+			roffEscape(cfg.Summary)))
+	} else {
+		sb.WriteString(roffLine(roffEscape(cfg.Name)))
+	}
+
+	sb.WriteString(".SH SYNOPSIS\n")
+	if len(cfg.Synopsis) == 0 {
+		sb.WriteString(roffLine("\\fB" + roffEscape(cfg.Name) + "\\fR"))
+	}
+	for i, line := range cfg.Synopsis {
+		if i > 0 {
+			sb.WriteString(".br\n")
+		}
+		if line == cfg.Name || strings.HasPrefix(line, cfg.Name+" ") {
+			line = "\\fB" + roffEscape(cfg.Name) + "\\fR" +
+				roffEscape(line[len(cfg.Name):])
+		} else {
+			line = roffEscape(line)
+		}
+		sb.WriteString(roffLine(line))
+	}
+
+	if len(strings.TrimSpace(cfg.Description)) > 0 {
+		sb.WriteString(".SH DESCRIPTION\n")
+		writeRoffParagraphs(&sb, cfg.Description, ".PP")
+	}
+
+	writeRoffOpts(&sb, "OPTIONS", selectOptCfgs(optCfgs, false))
+	writeRoffOpts(&sb, "GLOBAL OPTIONS", selectOptCfgs(optCfgs, true))
+
+	for _, sec := range cfg.Sections {
+		sb.WriteString(".SH " + roffEscape(strings.ToUpper(sec.Title)) + "\n")
+		writeRoffParagraphs(&sb, sec.Text, ".PP")
+	}
+
+	return sb.String()
+}
+
+func writeRoffOpts(sb *strings.Builder, title string, optCfgs []OptCfg) {
+	first := true
+	for _, cfg := range optCfgs {
+		if cfg.Name == anyOption {
+			continue
+		}
+		if first {
+			sb.WriteString(".SH " + title + "\n")
+			first = false
+		}
+		sb.WriteString(".TP\n")
+		sb.WriteString(roffLine(makeRoffOptTitle(cfg)))
+		writeRoffParagraphs(sb, makeOptDesc(cfg), ".IP")
+	}
+}
+
+func makeRoffOptTitle(cfg OptCfg) string {
+	names := make([]string, 0, len(cfg.Aliases)+1)
+	for _, name := range append([]string{cfg.Name}, cfg.Aliases...) {
+		names = append(names,
+			"\\fB"+roffEscape(optTitleWord(name, cfg.IsNegatable))+"\\fR")
+	}
+	title := strings.Join(names, ", ")
+
+	if argHelp := optArgHelp(cfg); cfg.HasArg && len(argHelp) > 0 {
+		if cfg.IsArgOptional {
+			title += "[=\\fI" + roffEscape(argHelp) + "\\fR]"
+		} else {
+			title += " \\fI" + roffEscape(argHelp) + "\\fR"
+		}
+	}
+	return title
+}
+
+func writeRoffParagraphs(sb *strings.Builder, text, macro string) {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+
+	isFirst := true
+	isBreak := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if len(line) == 0 {
+			isBreak = true
+			continue
+		}
+		if isFirst {
+			if macro == ".PP" {
+				sb.WriteString(".PP\n")
+			}
+			isFirst = false
+		} else if isBreak {
+			sb.WriteString(macro + "\n")
+		}
+		isBreak = false
+		sb.WriteString(roffLine(roffEscape(line)))
+	}
+}
+
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	return strings.ReplaceAll(s, "-", `\-`)
+}
+
+func roffLine(s string) string {
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s + "\n"
+}
+
+func roffQuote(s string) string {
+	return `"` + strings.ReplaceAll(roffEscape(s), `"`, `\(dq`) + `"`
+}
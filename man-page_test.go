@@ -0,0 +1,84 @@
+package cliargs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sttk/cliargs"
+)
+
+func TestMakeManPage(t *testing.T) {
+	manCfg := cliargs.ManPageCfg{
+		Name:     "my-app",
+		Date:     "2023-10-01",
+		Source:   "my-app 1.0.0",
+		Manual:   "User Commands",
+		Summary:  "does something",
+		Synopsis: []string{"my-app [OPTIONS] <file>...", "my-app --version"},
+		Description: "This is a description of my-app.\n" +
+			"It reads C:\\path\\to\\file.\n\n" +
+			".dot at the head of a line.",
+		Sections: []cliargs.ManSection{
+			cliargs.ManSection{Title: "See also", Text: "grep(1)"},
+		},
+	}
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name:    "foo-bar",
+			Aliases: []string{"f"},
+			Desc:    "FooBar is a flag.\n\nThis flag is foo bar.",
+		},
+		cliargs.OptCfg{
+			Name:    "baz",
+			HasArg:  true,
+			Desc:    "Baz is an option.",
+			ArgHelp: "<num>",
+		},
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}, IsGlobal: true},
+		cliargs.OptCfg{Name: "*"},
+	}
+
+	page := cliargs.MakeManPage(manCfg, optCfgs)
+	assert.Equal(t, page, `.\" This man page is generated by github.com/sttk/cliargs.
+.TH "MY\-APP" "1" "2023\-10\-01" "my\-app 1.0.0" "User Commands"
+.SH NAME
+my\-app \- does something
+.SH SYNOPSIS
+\fBmy\-app\fR [OPTIONS] <file>...
+.br
+\fBmy\-app\fR \-\-version
+.SH DESCRIPTION
+.PP
+This is a description of my\-app.
+It reads C:\epath\eto\efile.
+.PP
+\&.dot at the head of a line.
+.SH OPTIONS
+.TP
+\fB\-\-foo\-bar\fR, \fB\-f\fR
+FooBar is a flag.
+.IP
+This flag is foo bar.
+.TP
+\fB\-\-baz\fR \fI<num>\fR
+Baz is an option.
+.SH GLOBAL OPTIONS
+.TP
+\fB\-\-verbose\fR, \fB\-v\fR
+.SH SEE ALSO
+.PP
+grep(1)
+`)
+}
+
+func TestMakeManPage_minimum(t *testing.T) {
+	page := cliargs.MakeManPage(cliargs.ManPageCfg{Name: "app"}, nil)
+	assert.Equal(t, page, `.\" This man page is generated by github.com/sttk/cliargs.
+.TH "APP" "1" "" "" ""
+.SH NAME
+app
+.SH SYNOPSIS
+\fBapp\fR
+`)
+}
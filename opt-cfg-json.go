@@ -0,0 +1,184 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// UnknownCompHint is an error which indicates that the value of "compHint"
+// in a JSON representation of an option configuration is unknown.
+type UnknownCompHint struct{ Option, CompHint string }
+
+func (e UnknownCompHint) Error() string {
+	return fmt.Sprintf("UnknownCompHint{Option:%s,CompHint:%s}",
+		e.Option, e.CompHint)
+}
+
+// optCfgJSON is a structure which represents the JSON format of OptCfg.
+// The keys are fixed for compatibility, and a key of which value is zero is
+// omitted.
+// default is distinguished between null (no default value) and an empty
+// array.
+type optCfgJSON struct {
+	Name          string    `json:"name"`
+	Aliases       []string  `json:"aliases,omitempty"`
+	HasArg        bool      `json:"hasArg,omitempty"`
+	IsArray       bool      `json:"isArray,omitempty"`
+	Default       *[]string `json:"default,omitempty"`
+	Desc          string    `json:"desc,omitempty"`
+	ArgHelp       string    `json:"argHelp,omitempty"`
+	IsGlobal      bool      `json:"isGlobal,omitempty"`
+	CompHint      string    `json:"compHint,omitempty"`
+	IsNegatable   bool      `json:"isNegatable,omitempty"`
+	IsCounter     bool      `json:"isCounter,omitempty"`
+	Decrements    string    `json:"decrements,omitempty"`
+	IsArgOptional bool      `json:"isArgOptional,omitempty"`
+	ImplicitArg   string    `json:"implicitArg,omitempty"`
+	Arity         int       `json:"arity,omitempty"`
+	IsNumberOpt   bool      `json:"isNumberOpt,omitempty"`
+	IsRequired    bool      `json:"isRequired,omitempty"`
+	Choices       []string  `json:"choices,omitempty"`
+}
+
+var compHintNames = map[CompHint]string{
+	COMP_FILE: "file",
+	COMP_DIR:  "dir",
+}
+
+func parseCompHint(name string) (CompHint, bool) {
+	for hint, s := range compHintNames {
+		if s == name {
+			return hint, true
+		}
+	}
+	return COMP_NONE, false
+}
+
+// MarshalJSON is a method which returns the JSON representation of this
+// option configuration.
+// The JSON representation is an object which has the following keys:
+// "name", "aliases", "hasArg", "isArray", "default", "desc", "argHelp",
+// "isGlobal", "compHint", "isNegatable", "isCounter", "decrements",
+// "isArgOptional", "implicitArg", "arity", "isNumberOpt", "isRequired", and
+// "choices".
+// A key of which value is zero value (false, zero, empty string, empty array,
+// or COMP_NONE) is omitted, but "default" is omitted only when Default is nil.
+// The value of "compHint" is "file" or "dir".
+//
+// OnParsed and OnComplete are not included in the JSON representation because
+// functions cannot be serialized.
+// So they need to be set again after loading the JSON representation.
+func (cfg OptCfg) MarshalJSON() ([]byte, error) {
+	j := optCfgJSON{
+		Name:          cfg.Name,
+		Aliases:       cfg.Aliases,
+		HasArg:        cfg.HasArg,
+		IsArray:       cfg.IsArray,
+		Desc:          cfg.Desc,
+		ArgHelp:       cfg.ArgHelp,
+		IsGlobal:      cfg.IsGlobal,
+		CompHint:      compHintNames[cfg.CompHint],
+		IsNegatable:   cfg.IsNegatable,
+		IsCounter:     cfg.IsCounter,
+		Decrements:    cfg.Decrements,
+		IsArgOptional: cfg.IsArgOptional,
+		ImplicitArg:   cfg.ImplicitArg,
+		Arity:         cfg.Arity,
+		IsNumberOpt:   cfg.IsNumberOpt,
+		IsRequired:    cfg.IsRequired,
+		Choices:       cfg.Choices,
+	}
+	if cfg.Default != nil {
+		j.Default = &cfg.Default
+	}
+	return marshalJSON(j, "")
+}
+
+// UnmarshalJSON is a method which sets the fields of this option
+// configuration from its JSON representation.
+// (See MarshalJSON method about the JSON representation.)
+// Unknown keys are ignored so as to read a JSON representation which is
+// written by a newer version.
+// If the value of "compHint" is unknown, this method returns an error:
+// UnknownCompHint.
+func (cfg *OptCfg) UnmarshalJSON(data []byte) error {
+	var j optCfgJSON
+	err := json.Unmarshal(data, &j)
+	if err != nil {
+		return err
+	}
+
+	hint := COMP_NONE
+	if len(j.CompHint) > 0 {
+		var ok bool
+		hint, ok = parseCompHint(j.CompHint)
+		if !ok {
+			return UnknownCompHint{Option: j.Name, CompHint: j.CompHint}
+		}
+	}
+
+	*cfg = OptCfg{
+		Name:          j.Name,
+		Aliases:       j.Aliases,
+		HasArg:        j.HasArg,
+		IsArray:       j.IsArray,
+		Desc:          j.Desc,
+		ArgHelp:       j.ArgHelp,
+		IsGlobal:      j.IsGlobal,
+		CompHint:      hint,
+		IsNegatable:   j.IsNegatable,
+		IsCounter:     j.IsCounter,
+		Decrements:    j.Decrements,
+		IsArgOptional: j.IsArgOptional,
+		ImplicitArg:   j.ImplicitArg,
+		Arity:         j.Arity,
+		IsNumberOpt:   j.IsNumberOpt,
+		IsRequired:    j.IsRequired,
+		Choices:       j.Choices,
+	}
+	if j.Default != nil {
+		cfg.Default = *j.Default
+	}
+	return nil
+}
+
+// MarshalOptCfgs is a function which returns the JSON representation of an
+// array of option configurations.
+// The JSON representation is an array of the JSON representations of each
+// option configuration. (See OptCfg#MarshalJSON method.)
+func MarshalOptCfgs(optCfgs []OptCfg) ([]byte, error) {
+	if optCfgs == nil {
+		optCfgs = []OptCfg{}
+	}
+	return marshalJSON(optCfgs, "  ")
+}
+
+func marshalJSON(v any, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	err := enc.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// UnmarshalOptCfgs is a function which creates an array of option
+// configurations from its JSON representation, which is output by
+// MarshalOptCfgs function.
+// OnParsed and OnComplete fields of the created option configurations are nil.
+func UnmarshalOptCfgs(data []byte) ([]OptCfg, error) {
+	var optCfgs []OptCfg
+	err := json.Unmarshal(data, &optCfgs)
+	if err != nil {
+		return nil, err
+	}
+	return optCfgs, nil
+}
@@ -0,0 +1,177 @@
+package cliargs_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sttk/cliargs"
+)
+
+func TestMarshalOptCfgs(t *testing.T) {
+	onParsed := func(a []string) error { return nil }
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name:    "foo-bar",
+			Aliases: []string{"f"},
+			Desc:    "FooBar is a flag.",
+		},
+		cliargs.OptCfg{
+			Name:     "baz",
+			HasArg:   true,
+			IsArray:  true,
+			Default:  []string{"1", "2"},
+			OnParsed: &onParsed,
+			ArgHelp:  "<num>",
+			IsGlobal: true,
+			CompHint: cliargs.COMP_FILE,
+		},
+		cliargs.OptCfg{
+			Name:       "qux",
+			HasArg:     true,
+			Default:    []string{},
+			OnComplete: cliargs.CompleteChoices("a"),
+		},
+	}
+
+	data, err := cliargs.MarshalOptCfgs(optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), `[
+  {
+    "name": "foo-bar",
+    "aliases": [
+      "f"
+    ],
+    "desc": "FooBar is a flag."
+  },
+  {
+    "name": "baz",
+    "hasArg": true,
+    "isArray": true,
+    "default": [
+      "1",
+      "2"
+    ],
+    "argHelp": "<num>",
+    "isGlobal": true,
+    "compHint": "file"
+  },
+  {
+    "name": "qux",
+    "hasArg": true,
+    "default": []
+  }
+]`)
+
+	loaded, err := cliargs.UnmarshalOptCfgs(data)
+	assert.Nil(t, err)
+	assert.Equal(t, len(loaded), 3)
+
+	assert.Equal(t, loaded[0].Name, "foo-bar")
+	assert.Equal(t, loaded[0].Aliases, []string{"f"})
+	assert.False(t, loaded[0].HasArg)
+	assert.False(t, loaded[0].IsArray)
+	assert.Nil(t, loaded[0].Default)
+	assert.Equal(t, loaded[0].Desc, "FooBar is a flag.")
+
+	assert.Equal(t, loaded[1].Name, "baz")
+	assert.Nil(t, loaded[1].Aliases)
+	assert.True(t, loaded[1].HasArg)
+	assert.True(t, loaded[1].IsArray)
+	assert.Equal(t, loaded[1].Default, []string{"1", "2"})
+	assert.Nil(t, loaded[1].OnParsed)
+	assert.Equal(t, loaded[1].ArgHelp, "<num>")
+	assert.True(t, loaded[1].IsGlobal)
+	assert.Equal(t, loaded[1].CompHint, cliargs.COMP_FILE)
+
+	assert.Equal(t, loaded[2].Name, "qux")
+	assert.NotNil(t, loaded[2].Default)
+	assert.Equal(t, len(loaded[2].Default), 0)
+	assert.Nil(t, loaded[2].OnComplete)
+}
+
+func TestMarshalOptCfgs_empty(t *testing.T) {
+	data, err := cliargs.MarshalOptCfgs(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), `[]`)
+}
+
+func TestUnmarshalOptCfgs_ignoreUnknownKeys(t *testing.T) {
+	data := []byte(`[{"name":"foo","newKey":123,"compHint":"dir"}]`)
+	optCfgs, err := cliargs.UnmarshalOptCfgs(data)
+	assert.Nil(t, err)
+	assert.Equal(t, len(optCfgs), 1)
+	assert.Equal(t, optCfgs[0].Name, "foo")
+	assert.Equal(t, optCfgs[0].CompHint, cliargs.COMP_DIR)
+}
+
+func TestOptCfg_MarshalJSON_negatable(t *testing.T) {
+	cfg := cliargs.OptCfg{Name: "color", IsNegatable: true}
+	data, err := json.Marshal(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), `{"name":"color","isNegatable":true}`)
+
+	var cfg2 cliargs.OptCfg
+	err = json.Unmarshal(data, &cfg2)
+	assert.Nil(t, err)
+	assert.Equal(t, cfg2.Name, "color")
+	assert.True(t, cfg2.IsNegatable)
+}
+
+func TestUnmarshalOptCfgs_unknownCompHint(t *testing.T) {
+	data := []byte(`[{"name":"foo","compHint":"host"}]`)
+	optCfgs, err := cliargs.UnmarshalOptCfgs(data)
+	assert.Nil(t, optCfgs)
+	assert.Equal(t, err.Error(), "UnknownCompHint{Option:foo,CompHint:host}")
+	switch e := err.(type) {
+	case cliargs.UnknownCompHint:
+		assert.Equal(t, e.Option, "foo")
+		assert.Equal(t, e.CompHint, "host")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestUnmarshalOptCfgs_invalidJSON(t *testing.T) {
+	optCfgs, err := cliargs.UnmarshalOptCfgs([]byte(`{"name":"foo"}`))
+	assert.Nil(t, optCfgs)
+	assert.NotNil(t, err)
+}
+
+func TestOptCfg_MarshalJSON_inStruct(t *testing.T) {
+	type Spec struct {
+		Opts []cliargs.OptCfg `json:"opts"`
+	}
+	data, err := json.Marshal(Spec{Opts: []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "a", CompHint: cliargs.COMP_DIR},
+	}})
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), `{"opts":[{"name":"a","compHint":"dir"}]}`)
+}
+
+func TestOptCfg_MarshalJSON_required(t *testing.T) {
+	data, err := json.Marshal(cliargs.OptCfg{
+		Name: "output", HasArg: true, IsRequired: true})
+	assert.Nil(t, err)
+	assert.Equal(t, string(data),
+		`{"name":"output","hasArg":true,"isRequired":true}`)
+
+	var cfg cliargs.OptCfg
+	err = json.Unmarshal(data, &cfg)
+	assert.Nil(t, err)
+	assert.True(t, cfg.IsRequired)
+}
+
+func TestOptCfg_MarshalJSON_choices(t *testing.T) {
+	data, err := json.Marshal(cliargs.OptCfg{
+		Name: "format", HasArg: true, Choices: []string{"json", "yaml"}})
+	assert.Nil(t, err)
+	assert.Equal(t, string(data),
+		`{"name":"format","hasArg":true,"choices":["json","yaml"]}`)
+
+	var cfg cliargs.OptCfg
+	err = json.Unmarshal(data, &cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, cfg.Choices, []string{"json", "yaml"})
+}
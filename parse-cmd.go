@@ -0,0 +1,214 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargs
+
+import (
+	"fmt"
+	"path"
+)
+
+// UnconfiguredSubCmd is an error which indicates that there is no
+// configuration about the input sub command.
+type UnconfiguredSubCmd struct{ Name string }
+
+func (e UnconfiguredSubCmd) Error() string {
+	return fmt.Sprintf("UnconfiguredSubCmd{Name:%s}", e.Name)
+}
+
+// CmdCfg is a structure that represents a command configuration.
+// A command configuration consists of fields: Name, Aliases, OptCfgs,
+// ArgCfgs, Constraints, SubCmds, Desc, and OnComplete.
+//
+// Name is the command name and Aliases are the another names.
+// A sub command given by those names in command line arguments is registered
+// to Cmd with the Name.
+// The Name of the top command configuration is not used for parsing, because
+// the command name is taken from the first element of command line arguments.
+//
+// OptCfgs is the array of option configurations which are available for this
+// command.
+//
+// ArgCfgs is the array of command argument configurations which check the
+// command arguments of this command. (See ArgCfg type.)
+// If this field is nil, any command arguments are accepted, and if this field
+// is an empty array, no command argument is accepted.
+// This field is ignored for a command which has sub commands.
+//
+// Constraints is the array of option constraints among the options in OptCfgs.
+// (See OptConstraint type.)
+//
+// SubCmds is the array of command configurations of the sub commands of this
+// command.
+//
+// Desc is the field to set the description of the command.
+//
+// OnComplete is the field for the event handler which is called when a
+// command argument is completed dynamically by the hidden "__complete" sub
+// command. (See RunCompletion function.)
+// This handler receives a Cmd which is parsed from the command line arguments
+// before the completed one, and the prefix of the command argument, and
+// returns candidates of the command argument.
+type CmdCfg struct {
+	Name        string
+	Aliases     []string
+	OptCfgs     []OptCfg
+	ArgCfgs     []ArgCfg
+	Constraints []OptConstraint
+	SubCmds     []CmdCfg
+	Desc        string
+	OnComplete  *func(Cmd, string) []string
+}
+
+// ParseCmd is a function which parses command line arguments including sub
+// commands with a command configuration.
+//
+// This function parses the options of the top command with OptCfgs of the
+// command configuration until the first command argument.
+// If the command configuration has sub command configurations, the first
+// command argument have to be the Name or one of the Aliases of them, and
+// the remaining command line arguments are parsed with the selected sub
+// command configuration recursively.
+// If the first command argument matches no sub command configuration, this
+// function returns UnconfiguredSubCmd error.
+// The command line arguments after "--" are the command arguments of the
+// command, and are not taken as sub command names.
+// A command configuration which has no sub command configurations is parsed
+// in the same way as ParseWith function.
+//
+// The returned Cmd instance is the top command, and the selected sub command
+// can be obtained with Cmd#SubCmd method.
+//
+// An option of which OptCfg#IsGlobal is true is accepted also after the names
+// of the sub commands of the command where the option is configured.
+// Its option arguments are registered to the Cmd of the configured command,
+// and Cmd#HasOpt, Cmd#OptArg, and Cmd#OptArgs of the sub commands can also
+// obtain them.
+//
+// The behavior of parsing can be changed by ParseOpt(s) which are given as
+// variadic arguments, and they are applied to the sub commands, too.
+func ParseCmd(
+	osArgs []string, cmdCfg CmdCfg, parseOpts ...ParseOpt,
+) (Cmd, error) {
+	var cmdName string
+	if len(osArgs) > 0 {
+		cmdName = path.Base(osArgs[0])
+	}
+
+	var osArgs1 []string
+	if len(osArgs) > 1 {
+		osArgs1 = osArgs[1:]
+	}
+
+	pc := newParseCfg(parseOpts)
+
+	if pc.responseFiles {
+		var err error
+		osArgs1, err = expandResponseFiles(osArgs1)
+		if err != nil {
+			return Cmd{args: empty}, err
+		}
+	}
+
+	cmd, err := parseCmd(osArgs1, cmdCfg, cmdName, nil, nil, pc)
+	if err != nil {
+		return Cmd{args: empty}, err
+	}
+
+	return *cmd, nil
+}
+
+func parseCmd(
+	osArgs []string,
+	cmdCfg CmdCfg,
+	cmdName string,
+	globals []globalOpts,
+	parent *Cmd,
+	pc parseCfg,
+) (*Cmd, error) {
+	pc.untilFirstArg = len(cmdCfg.SubCmds) > 0
+
+	args, opts, iArg, err := parseWith(osArgs, cmdCfg.OptCfgs, globals, pc)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := &Cmd{Name: cmdName, args: args, opts: opts, parent: parent}
+	cmd.globals = globalNames(cmdCfg.OptCfgs)
+	cmd.arities = arityMap(cmdCfg.OptCfgs)
+
+	if len(cmdCfg.SubCmds) == 0 {
+		err = checkArgs(cmdCfg.ArgCfgs, args)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if iArg >= 0 {
+		subCfg, exists := findSubCmdCfg(cmdCfg.SubCmds, osArgs[iArg])
+		if !exists {
+			return nil, UnconfiguredSubCmd{Name: osArgs[iArg]}
+		}
+
+		subGlobals := appendGlobalOpts(globals, cmdCfg.OptCfgs, cmd)
+
+		cmd.subCmd, err = parseCmd(
+			osArgs[iArg+1:], subCfg, subCfg.Name, subGlobals, cmd, pc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Applies constraints, default values, and event handlers after parsing
+	// sub commands, because global options can be specified after sub command
+	// names.
+	err = applyConstraints(cmdCfg.Constraints, cmdCfg.OptCfgs, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = applyOptCfgs(cmdCfg.OptCfgs, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+func globalNames(optCfgs []OptCfg) map[string]bool {
+	var names map[string]bool
+	for _, cfg := range optCfgs {
+		if cfg.IsGlobal && cfg.Name != anyOption {
+			if names == nil {
+				names = make(map[string]bool)
+			}
+			names[cfg.Name] = true
+		}
+	}
+	return names
+}
+
+func appendGlobalOpts(
+	globals []globalOpts, optCfgs []OptCfg, cmd *Cmd,
+) []globalOpts {
+	if cmd.globals == nil {
+		return globals
+	}
+	subGlobals := make([]globalOpts, len(globals), len(globals)+1)
+	copy(subGlobals, globals)
+	return append(subGlobals, globalOpts{optCfgs, cmd.opts})
+}
+
+func findSubCmdCfg(subCmdCfgs []CmdCfg, name string) (CmdCfg, bool) {
+	for _, cfg := range subCmdCfgs {
+		if cfg.Name == name {
+			return cfg, true
+		}
+		for _, a := range cfg.Aliases {
+			if a == name {
+				return cfg, true
+			}
+		}
+	}
+	return CmdCfg{}, false
+}
@@ -0,0 +1,508 @@
+package cliargs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sttk/cliargs"
+)
+
+func TestParseCmd_zeroArg(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{}
+
+	osArgs := []string{}
+
+	cmd, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.Name, "")
+	assert.Equal(t, cmd.Args(), []string{})
+	_, exists := cmd.SubCmd()
+	assert.False(t, exists)
+}
+
+func TestParseCmd_noSubCmdCfgs(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "foo", Aliases: []string{"f"}},
+		},
+	}
+
+	osArgs := []string{"path/to/app", "bar", "-f", "baz"}
+
+	cmd, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.Name, "app")
+	assert.True(t, cmd.HasOpt("foo"))
+	assert.Equal(t, cmd.Args(), []string{"bar", "baz"})
+	_, exists := cmd.SubCmd()
+	assert.False(t, exists)
+}
+
+func TestParseCmd_selectSubCmdByName(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name: "list",
+				OptCfgs: []cliargs.OptCfg{
+					cliargs.OptCfg{Name: "all", Aliases: []string{"a"}},
+				},
+			},
+			cliargs.CmdCfg{
+				Name: "use",
+				OptCfgs: []cliargs.OptCfg{
+					cliargs.OptCfg{Name: "version", HasArg: true},
+				},
+			},
+		},
+	}
+
+	osArgs := []string{"path/to/app", "-v", "use", "--version", "1.2", "x"}
+
+	cmd, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.Name, "app")
+	assert.True(t, cmd.HasOpt("verbose"))
+	assert.False(t, cmd.HasOpt("version"))
+	assert.Equal(t, cmd.Args(), []string{})
+
+	sub, exists := cmd.SubCmd()
+	assert.True(t, exists)
+	assert.Equal(t, sub.Name, "use")
+	assert.False(t, sub.HasOpt("verbose"))
+	assert.True(t, sub.HasOpt("version"))
+	assert.Equal(t, sub.OptArg("version"), "1.2")
+	assert.Equal(t, sub.Args(), []string{"x"})
+	_, exists = sub.SubCmd()
+	assert.False(t, exists)
+}
+
+func TestParseCmd_selectSubCmdByAlias(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{Name: "list", Aliases: []string{"ls"}},
+		},
+	}
+
+	osArgs := []string{"app", "ls", "foo"}
+
+	cmd, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	assert.Nil(t, err)
+	sub, exists := cmd.SubCmd()
+	assert.True(t, exists)
+	assert.Equal(t, sub.Name, "list")
+	assert.Equal(t, sub.Args(), []string{"foo"})
+}
+
+func TestParseCmd_optionTakingArgBeforeSubCmd(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "config", HasArg: true},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{Name: "list"},
+		},
+	}
+
+	osArgs := []string{"app", "--config", "a.yml", "list"}
+
+	cmd, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("config"), "a.yml")
+	sub, exists := cmd.SubCmd()
+	assert.True(t, exists)
+	assert.Equal(t, sub.Name, "list")
+}
+
+func TestParseCmd_nestedSubCmds(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name: "remote",
+				SubCmds: []cliargs.CmdCfg{
+					cliargs.CmdCfg{
+						Name: "add",
+						OptCfgs: []cliargs.OptCfg{
+							cliargs.OptCfg{Name: "f"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	osArgs := []string{"git", "remote", "add", "-f", "origin", "url"}
+
+	cmd, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.Name, "git")
+
+	sub, exists := cmd.SubCmd()
+	assert.True(t, exists)
+	assert.Equal(t, sub.Name, "remote")
+	assert.Equal(t, sub.Args(), []string{})
+
+	subsub, exists := sub.SubCmd()
+	assert.True(t, exists)
+	assert.Equal(t, subsub.Name, "add")
+	assert.True(t, subsub.HasOpt("f"))
+	assert.Equal(t, subsub.Args(), []string{"origin", "url"})
+}
+
+func TestParseCmd_noSubCmdIsSpecified(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "help"},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{Name: "list"},
+		},
+	}
+
+	osArgs := []string{"app", "--help"}
+
+	cmd, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("help"))
+	_, exists := cmd.SubCmd()
+	assert.False(t, exists)
+}
+
+func TestParseCmd_unconfiguredSubCmd(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{Name: "list"},
+		},
+	}
+
+	osArgs := []string{"app", "lsit"}
+
+	cmd, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "UnconfiguredSubCmd{Name:lsit}")
+	switch err.(type) {
+	case cliargs.UnconfiguredSubCmd:
+		assert.Equal(t, err.(cliargs.UnconfiguredSubCmd).Name, "lsit")
+	default:
+		assert.Fail(t, err.Error())
+	}
+	assert.Equal(t, cmd.Name, "")
+	assert.Equal(t, cmd.Args(), []string{})
+}
+
+func TestParseCmd_errorInSubCmd(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{Name: "list"},
+		},
+	}
+
+	osArgs := []string{"app", "list", "--foo"}
+
+	_, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "UnconfiguredOption{Option:foo}")
+}
+
+func TestParseCmd_subCmdAfterDoubleHyphens(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{Name: "list"},
+		},
+	}
+
+	osArgs := []string{"app", "--", "list", "--foo"}
+
+	cmd, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.Name, "app")
+	assert.Equal(t, cmd.Args(), []string{"list", "--foo"})
+	_, exists := cmd.SubCmd()
+	assert.False(t, exists)
+
+	osArgs = []string{"app", "--", "-x"}
+
+	cmd, err = cliargs.ParseCmd(osArgs, cmdCfg)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.Args(), []string{"-x"})
+}
+
+func TestParseCmd_globalOptAfterSubCmd(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}, IsGlobal: true},
+			cliargs.OptCfg{Name: "config", HasArg: true, IsGlobal: true},
+			cliargs.OptCfg{Name: "local"},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name: "remote",
+				SubCmds: []cliargs.CmdCfg{
+					cliargs.CmdCfg{Name: "add"},
+				},
+			},
+		},
+	}
+
+	osArgs := []string{"git", "remote", "-v", "add", "--config", "a.yml", "x"}
+
+	cmd, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("verbose"))
+	assert.Equal(t, cmd.OptArg("config"), "a.yml")
+
+	sub, _ := cmd.SubCmd()
+	assert.True(t, sub.HasOpt("verbose"))
+	assert.Equal(t, sub.OptArg("config"), "a.yml")
+
+	leaf, _ := sub.SubCmd()
+	assert.Equal(t, leaf.Name, "add")
+	assert.True(t, leaf.HasOpt("verbose"))
+	assert.Equal(t, leaf.OptArg("config"), "a.yml")
+	assert.Equal(t, leaf.OptArgs("config"), []string{"a.yml"})
+	assert.False(t, leaf.HasOpt("local"))
+	assert.Equal(t, leaf.Args(), []string{"x"})
+}
+
+func TestParseCmd_nonGlobalOptAfterSubCmd(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "local"},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{Name: "list"},
+		},
+	}
+
+	osArgs := []string{"app", "list", "--local"}
+
+	_, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "UnconfiguredOption{Option:local}")
+}
+
+func TestParseCmd_globalOptIsNotArray(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "config", HasArg: true, IsGlobal: true},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{Name: "list"},
+		},
+	}
+
+	osArgs := []string{"app", "--config", "a", "list", "--config", "b"}
+
+	_, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "OptionIsNotArray{Option:config}")
+}
+
+func TestParseCmd_globalOptDefaultAndOnParsed(t *testing.T) {
+	var level string
+	var onParsed = func(a []string) error {
+		level = a[0]
+		return nil
+	}
+
+	cmdCfg := cliargs.CmdCfg{
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:     "level",
+				HasArg:   true,
+				Default:  []string{"info"},
+				OnParsed: &onParsed,
+				IsGlobal: true,
+			},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{Name: "list"},
+		},
+	}
+
+	cmd, err := cliargs.ParseCmd([]string{"app", "list"}, cmdCfg)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("level"), "info")
+	assert.Equal(t, level, "info")
+
+	cmd, err = cliargs.ParseCmd([]string{"app", "list", "--level=debug"}, cmdCfg)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("level"), "debug")
+	sub, _ := cmd.SubCmd()
+	assert.Equal(t, sub.OptArg("level"), "debug")
+	assert.Equal(t, level, "debug")
+}
+
+func TestParseCmd_localOptOverridesGlobalOpt(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "name", HasArg: true, IsGlobal: true},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name: "list",
+				OptCfgs: []cliargs.OptCfg{
+					cliargs.OptCfg{Name: "name", HasArg: true, IsArray: true},
+				},
+			},
+		},
+	}
+
+	osArgs := []string{"app", "list", "--name", "a", "--name", "b"}
+
+	cmd, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	assert.Nil(t, err)
+	assert.False(t, cmd.HasOpt("name"))
+	sub, _ := cmd.SubCmd()
+	assert.Equal(t, sub.OptArgs("name"), []string{"a", "b"})
+}
+
+func TestParseCmd_attachedShortArgs(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "C", HasArg: true, IsGlobal: true},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name: "get",
+				OptCfgs: []cliargs.OptCfg{
+					cliargs.OptCfg{Name: "o", HasArg: true},
+				},
+			},
+		},
+	}
+
+	osArgs := []string{"app", "get", "-ojson", "-Cdir", "x"}
+	cmd, err := cliargs.ParseCmd(osArgs, cmdCfg, cliargs.WithAttachedShortArgs())
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("C"), "dir")
+	sub, ok := cmd.SubCmd()
+	assert.True(t, ok)
+	assert.Equal(t, sub.OptArg("o"), "json")
+	assert.Equal(t, sub.Args(), []string{"x"})
+}
+
+func TestParseCmd_negatableGlobalOptAfterSubCmd(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "color", IsGlobal: true, IsNegatable: true},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{Name: "log"},
+		},
+	}
+
+	osArgs := []string{"git", "--color", "log", "--no-color"}
+
+	cmd, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	assert.Nil(t, err)
+	assert.False(t, cmd.HasOpt("color"))
+
+	sub, _ := cmd.SubCmd()
+	assert.False(t, sub.HasOpt("color"))
+}
+
+func TestParseCmd_globalCounterOptAfterSubCmd(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name: "verbose", Aliases: []string{"v"},
+				IsCounter: true, IsGlobal: true,
+			},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name: "log",
+				OptCfgs: []cliargs.OptCfg{
+					cliargs.OptCfg{Name: "q", Decrements: "verbose"},
+				},
+			},
+		},
+	}
+
+	osArgs := []string{"git", "-vv", "log", "-vvq"}
+
+	cmd, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("verbose"), "3")
+
+	sub, _ := cmd.SubCmd()
+	assert.Equal(t, sub.OptArg("verbose"), "3")
+	assert.False(t, sub.HasOpt("q"))
+}
+
+func TestParseCmd_globalArityOptAfterSubCmd(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name: "rename", HasArg: true, IsArray: true, Arity: 2,
+				IsGlobal: true,
+			},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{Name: "mv"},
+		},
+	}
+
+	osArgs := []string{"app", "--rename", "a", "b", "mv", "--rename", "c", "d"}
+
+	cmd, err := cliargs.ParseCmd(osArgs, cmdCfg)
+	assert.Nil(t, err)
+
+	sub, _ := cmd.SubCmd()
+	assert.Equal(t, sub.OptArgGroups("rename"), [][]string{{"a", "b"}, {"c", "d"}})
+	assert.Equal(t, sub.Args(), []string{})
+}
+
+func TestParseCmd_requiredGlobalOpt(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:       "config",
+				HasArg:     true,
+				IsGlobal:   true,
+				IsRequired: true,
+			},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{Name: "list"},
+		},
+	}
+
+	cmd, err := cliargs.ParseCmd([]string{"app", "list", "--config", "x"}, cmdCfg)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("config"), "x")
+
+	_, err = cliargs.ParseCmd([]string{"app", "list"}, cmdCfg)
+	assert.Equal(t, err.Error(), "MissingRequiredOption{Option:config,Aliases:[]}")
+}
+
+func TestParseCmd_longOptAbbrevWithShadowedGlobalOpt(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "verbose", IsGlobal: true},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name: "list",
+				OptCfgs: []cliargs.OptCfg{
+					cliargs.OptCfg{Name: "verbose", HasArg: true},
+				},
+			},
+		},
+	}
+
+	osArgs := []string{"app", "--verb", "list", "--verbo", "x"}
+	cmd, err := cliargs.ParseCmd(osArgs, cmdCfg, cliargs.WithLongOptAbbrev())
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("verbose"))
+
+	sub, _ := cmd.SubCmd()
+	assert.Equal(t, sub.Name, "list")
+	assert.Equal(t, sub.OptArg("verbose"), "x")
+}
@@ -109,6 +109,11 @@ func (e IllegalOptionType) Error() string {
 // arguments.
 // If the type is an array, the option can takes multiple option arguments,
 // therefore it can appear multiple times in command line arguments.
+// If the type is a fixed size array or a struct of which elements or exported
+// fields are booleans, numbers, or strings, like [2]string or
+// struct{ X, Y int }, the option takes as many option arguments as them per
+// occurrence. (See OptCfg#Arity.)
+// And a slice of such type can appear multiple times, like [][2]string.
 //
 // A struct tag can specify an option name, aliases, and a default value.
 // It has a special format, like `opt:foo-bar,f=123`.
@@ -123,6 +128,8 @@ func (e IllegalOptionType) Error() string {
 // The string after the "=" mark is default value(s).
 // If the type of the option is a boolean, the string after "=" mark is ignored
 // because a boolean option takes no option argument.
+// (But a boolean option can take a boolean value attached with "=" in command
+// line arguments if WithBoolOptArgs is given.)
 // If the type of the option is a number or a string, the whole string after
 // "=" mark is a default value.
 // If the type of the option is an array, the string after "=" mark have to be
@@ -132,18 +139,57 @@ func (e IllegalOptionType) Error() string {
 // before the open square bracket, like :[elem1:elem2:elem3].
 // It's useful when some array elements include commas.
 //
+// If the option name is prefixed with "[no-]", like `optcfg:"[no-]color"`,
+// the option is negatable and also accepts "--no-" followed by its name or its
+// long alias. (See OptCfg#IsNegatable.)
+// This marker is available only for a boolean option, and is useful to turn
+// off a boolean field of which initial value is true.
+//
+// If the option name is prefixed with "+", like `optcfg:"+verbose,v"`, the
+// option is a counter which counts its occurrences, like -vvv.
+// (See OptCfg#IsCounter.)
+// This marker is available only for an integer option, and the field is set
+// to the number of the occurrences.
+// A counter option can also be negatable, like `optcfg:"[no-]+verbose"`.
+//
+// If the option name is prefixed with "!", like `optcfg:"!output,o"`, the
+// option is required. (See OptCfg#IsRequired.)
+// This marker is put before the other markers, like `optcfg:"![no-]color"`.
+//
+// The struct tag optimplicit makes the option argument optional, and its value
+// is the option argument for when the option is given without an option
+// argument, like `optcfg:"color=never" optimplicit:"always"`.
+// (See OptCfg#IsArgOptional.)
+// This struct tag is ignored for a boolean option and a counter option.
+//
+// The struct tag optdecrements makes the option decrement a counter option of
+// which name is its value, like `optcfg:"quiet,q" optdecrements:"verbose"`.
+// (See OptCfg#Decrements.)
+// This struct tag is available only for a boolean option, and the field of the
+// option is not set because the option is not registered to Cmd by itself.
+//
+// The struct tag optchoices limits the option argument to the allowed values
+// which are separated by commas, like `optcfg:"format" optchoices:"json,yaml"`.
+// (See OptCfg#Choices.)
+// This struct tag is ignored for a boolean option and a counter option.
+//
 // NOTE: A default value of a string array option in a struct tag is [], like
 // `opt:"name=[]"`, it doesn't represent an array which contains only an empty
 // string but an empty array.
 // If you want to specify an array which contains only an empty string, write
 // nothing after "=" mark, like `opt:"name="`.
-func ParseFor(osArgs []string, options any) (Cmd, []OptCfg, error) {
+//
+// The behavior of parsing can be changed by ParseOpt(s) which are given as
+// variadic arguments. (See ParseOpt type.)
+func ParseFor(
+	osArgs []string, options any, parseOpts ...ParseOpt,
+) (Cmd, []OptCfg, error) {
 	optCfgs, err := MakeOptCfgsFor(options)
 	if err != nil {
 		return Cmd{args: empty}, optCfgs, err
 	}
 
-	cmd, err := ParseWith(osArgs, optCfgs)
+	cmd, err := ParseWith(osArgs, optCfgs, parseOpts...)
 	return cmd, optCfgs, err
 }
 
@@ -165,6 +211,14 @@ func MakeOptCfgsFor(options any) ([]OptCfg, error) {
 	for i := 0; i < n; i++ {
 		optCfgs[i] = newOptCfg(t.Field(i))
 
+		if optCfgs[i].IsCounter && !isIntKind(t.Field(i).Type.Kind()) {
+			return nil, IllegalOptionType{
+				Option: optCfgs[i].Name,
+				Field:  t.Field(i).Name,
+				Type:   t.Field(i).Type,
+			}
+		}
+
 		var setter func([]string) error
 		setter, err = newValueSetter(optCfgs[i].Name, t.Field(i).Name, v.Field(i))
 		if err != nil {
@@ -176,11 +230,35 @@ func MakeOptCfgsFor(options any) ([]OptCfg, error) {
 	return optCfgs, nil
 }
 
+const (
+	requiredMarker = "!"
+	negMarker      = "[no-]"
+	counterMarker  = "+"
+)
+
 func newOptCfg(fld reflect.StructField) OptCfg {
 	opt := fld.Tag.Get("optcfg")
 	arr := strings.SplitN(opt, "=", 2)
 	names := strings.Split(arr[0], ",")
 
+	isRequired := false
+	if strings.HasPrefix(names[0], requiredMarker) {
+		names[0] = names[0][len(requiredMarker):]
+		isRequired = true
+	}
+
+	isNegatable := false
+	if strings.HasPrefix(names[0], negMarker) {
+		names[0] = names[0][len(negMarker):]
+		isNegatable = true
+	}
+
+	isCounter := false
+	if strings.HasPrefix(names[0], counterMarker) {
+		names[0] = names[0][len(counterMarker):]
+		isCounter = true
+	}
+
 	var name string
 	var aliases []string
 	if len(names) == 0 || len(names[0]) == 0 {
@@ -199,12 +277,20 @@ func newOptCfg(fld reflect.StructField) OptCfg {
 	case reflect.Bool:
 		hasArg = false
 	}
+	if isCounter {
+		hasArg = false
+	}
+
+	arity := groupArity(fld.Type)
+	if isArray {
+		arity = groupArity(fld.Type.Elem())
+	}
 
 	var defaults []string
 	if len(arr) > 1 && hasArg {
 		def := arr[1]
 		n := len(def)
-		if !isArray {
+		if !isArray && arity == 0 {
 			defaults = []string{def}
 		} else if n > 1 && def[0] == '[' && def[n-1] == ']' {
 			defs := def[1 : n-1]
@@ -226,20 +312,75 @@ func newOptCfg(fld reflect.StructField) OptCfg {
 	}
 
 	var optArg string
+	var implicitArg string
+	var choices []string
+	isArgOptional := false
 	if hasArg {
 		optArg = fld.Tag.Get("optarg")
+		implicitArg, isArgOptional = fld.Tag.Lookup("optimplicit")
+		if s := fld.Tag.Get("optchoices"); len(s) > 0 {
+			choices = strings.Split(s, ",")
+		}
 	}
 
+	decrements := fld.Tag.Get("optdecrements")
+
 	desc := fld.Tag.Get("optdesc")
 
 	return OptCfg{
-		Name:    name,
-		Aliases: aliases,
-		HasArg:  hasArg,
-		IsArray: isArray,
-		Default: defaults,
-		Desc:    desc,
-		ArgHelp: optArg,
+		Name:          name,
+		Aliases:       aliases,
+		HasArg:        hasArg,
+		IsArray:       isArray,
+		Default:       defaults,
+		Desc:          desc,
+		ArgHelp:       optArg,
+		IsNegatable:   isNegatable,
+		IsCounter:     isCounter,
+		Decrements:    decrements,
+		IsArgOptional: isArgOptional,
+		ImplicitArg:   implicitArg,
+		Arity:         arity,
+		IsRequired:    isRequired,
+		Choices:       choices,
+	}
+}
+
+// groupArity is a function which returns the number of option arguments per
+// occurrence if the type groups them, that is an array or a struct of which
+// elements or fields are booleans, numbers, or strings.
+// Otherwise, this function returns zero.
+func groupArity(t reflect.Type) int {
+	switch t.Kind() {
+	case reflect.Array:
+		if isScalarKind(t.Elem().Kind()) {
+			return t.Len()
+		}
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() || !isScalarKind(f.Type.Kind()) {
+				return 0
+			}
+		}
+		return t.NumField()
+	}
+	return 0
+}
+
+func isScalarKind(k reflect.Kind) bool {
+	return k == reflect.Bool || k == reflect.String || isIntKind(k) ||
+		k == reflect.Float32 || k == reflect.Float64
+}
+
+func isIntKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		return true
+	default:
+		return false
 	}
 }
 
@@ -249,6 +390,9 @@ func newValueSetter(
 	fld reflect.Value,
 ) (func([]string) error, error) {
 	t := fld.Type()
+	if n := groupArity(t); n > 0 {
+		return newGroupSetter(optName, fldName, fld, n)
+	}
 	switch t.Kind() {
 	case reflect.Bool:
 		return newBoolSetter(optName, fldName, fld)
@@ -306,6 +450,9 @@ func newValueSetter(
 		case reflect.String:
 			return newStringArraySetter(optName, fldName, fld)
 		default:
+			if n := groupArity(elm); n > 0 {
+				return newGroupArraySetter(optName, fldName, fld, n)
+			}
 			return newIllegalOptionTypeErr(optName, fldName, t)
 		}
 	case reflect.String:
@@ -325,9 +472,19 @@ func newBoolSetter(
 	optName string, fldName string, fld reflect.Value,
 ) (func([]string) error, error) {
 	fn := func(s []string) error {
-		if s != nil {
+		if s == nil {
+			return nil
+		}
+		// A negated option receives an array which has only "false".
+		if len(s) == 0 {
 			fld.SetBool(true)
+			return nil
+		}
+		b, ok := parseBoolOptArg(s[0])
+		if !ok {
+			return OptionArgIsNotBool{Option: optName, OptArg: s[0]}
 		}
+		fld.SetBool(b)
 		return nil
 	}
 	return fn, nil
@@ -509,3 +666,62 @@ func newStringArraySetter(
 	}
 	return fn, nil
 }
+
+func newGroupSetter(
+	optName string, fldName string, fld reflect.Value, n int,
+) (func([]string) error, error) {
+	fn := func(s []string) error {
+		if len(s) < n {
+			return nil
+		}
+		return setGroup(optName, fldName, fld, s[0:n])
+	}
+	return fn, nil
+}
+
+func newGroupArraySetter(
+	optName string, fldName string, fld reflect.Value, n int,
+) (func([]string) error, error) {
+	fn := func(s []string) error {
+		if s == nil {
+			return nil
+		}
+		t := fld.Type().Elem()
+		arr := reflect.MakeSlice(fld.Type(), 0, len(s)/n)
+		for i := 0; i+n <= len(s); i += n {
+			v := reflect.New(t).Elem()
+			err := setGroup(optName, fldName, v, s[i:i+n])
+			if err != nil {
+				return err
+			}
+			arr = reflect.Append(arr, v)
+		}
+		fld.Set(arr)
+		return nil
+	}
+	return fn, nil
+}
+
+// setGroup is a function which sets option arguments of an occurrence to the
+// elements of an array or the fields of a struct in order.
+func setGroup(
+	optName string, fldName string, v reflect.Value, s []string,
+) error {
+	for i := range s {
+		var elm reflect.Value
+		if v.Kind() == reflect.Array {
+			elm = v.Index(i)
+		} else {
+			elm = v.Field(i)
+		}
+		setter, err := newValueSetter(optName, fldName, elm)
+		if err != nil {
+			return err
+		}
+		err = setter(s[i : i+1])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
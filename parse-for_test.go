@@ -1632,7 +1632,7 @@ func TestMakeOptCfgsFor_optionParam(t *testing.T) {
 }
 
 func TestParseFor_optCfgHasUnsupportedType(t *testing.T) {
-	type A struct{ Name string }
+	type A struct{ Names []string }
 	type MyOptions struct {
 		FooBar A `optcfg:"foo-bar,f" optdesc:"FooBar description"`
 	}
@@ -1674,3 +1674,304 @@ func TestParseFor_argIsNotPointer(t *testing.T) {
 		assert.Fail(t, err.Error())
 	}
 }
+
+func TestParseFor_negatableBool(t *testing.T) {
+	type MyOptions struct {
+		Color   bool `optcfg:"[no-]color,c" optdesc:"Colorize output."`
+		Verbose bool `optcfg:"[no-]"`
+	}
+
+	options := MyOptions{Color: true, Verbose: true}
+	optCfgs, err := cliargs.MakeOptCfgsFor(&options)
+	assert.Nil(t, err)
+	assert.Equal(t, optCfgs[0].Name, "color")
+	assert.Equal(t, optCfgs[0].Aliases, []string{"c"})
+	assert.True(t, optCfgs[0].IsNegatable)
+	assert.Equal(t, optCfgs[1].Name, "Verbose")
+	assert.True(t, optCfgs[1].IsNegatable)
+
+	osArgs := []string{"app", "--no-color", "--no-Verbose", "-c"}
+	_, _, err = cliargs.ParseFor(osArgs, &options)
+	assert.Nil(t, err)
+	assert.True(t, options.Color)
+	assert.False(t, options.Verbose)
+
+	options = MyOptions{Color: true}
+	osArgs = []string{"app", "-c", "--no-color"}
+	_, _, err = cliargs.ParseFor(osArgs, &options)
+	assert.Nil(t, err)
+	assert.False(t, options.Color)
+
+	options = MyOptions{Color: true}
+	osArgs = []string{"app"}
+	_, _, err = cliargs.ParseFor(osArgs, &options)
+	assert.Nil(t, err)
+	assert.True(t, options.Color)
+}
+
+func TestParseFor_negatableNotBool(t *testing.T) {
+	type MyOptions struct {
+		Level int `optcfg:"[no-]level"`
+	}
+
+	options := MyOptions{}
+	_, _, err := cliargs.ParseFor([]string{"app"}, &options)
+	switch e := err.(type) {
+	case cliargs.ConfigIsNegatableButHasArg:
+		assert.Equal(t, e.Option, "level")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseFor_boolOptArgs(t *testing.T) {
+	type MyOptions struct {
+		DryRun  bool `optcfg:"dry-run"`
+		Verbose bool `optcfg:"verbose,v"`
+	}
+
+	options := MyOptions{DryRun: true}
+	osArgs := []string{"app", "--dry-run=off", "-v=yes"}
+	_, _, err := cliargs.ParseFor(osArgs, &options, cliargs.WithBoolOptArgs())
+	assert.Nil(t, err)
+	assert.False(t, options.DryRun)
+	assert.True(t, options.Verbose)
+
+	options = MyOptions{}
+	optCfgs, err := cliargs.MakeOptCfgsFor(&options)
+	assert.Nil(t, err)
+	err = (*optCfgs[0].OnParsed)([]string{"1"})
+	assert.Nil(t, err)
+	assert.True(t, options.DryRun)
+	err = (*optCfgs[0].OnParsed)([]string{"x"})
+	switch e := err.(type) {
+	case cliargs.OptionArgIsNotBool:
+		assert.Equal(t, e.Option, "dry-run")
+		assert.Equal(t, e.OptArg, "x")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseFor_counter(t *testing.T) {
+	type MyOptions struct {
+		Verbose int  `optcfg:"+verbose,v"`
+		Level   uint `optcfg:"[no-]+level,l=9"`
+		Debug   bool `optcfg:"debug,d"`
+	}
+
+	options := MyOptions{}
+	optCfgs, err := cliargs.MakeOptCfgsFor(&options)
+	assert.Nil(t, err)
+	assert.Equal(t, optCfgs[0].Name, "verbose")
+	assert.True(t, optCfgs[0].IsCounter)
+	assert.False(t, optCfgs[0].HasArg)
+	assert.Equal(t, optCfgs[1].Name, "level")
+	assert.True(t, optCfgs[1].IsCounter)
+	assert.True(t, optCfgs[1].IsNegatable)
+	assert.Nil(t, optCfgs[1].Default)
+
+	osArgs := []string{"app", "-vvdv", "-ll"}
+	_, _, err = cliargs.ParseFor(osArgs, &options)
+	assert.Nil(t, err)
+	assert.Equal(t, options.Verbose, 3)
+	assert.Equal(t, options.Level, uint(2))
+	assert.True(t, options.Debug)
+
+	options = MyOptions{Level: 5}
+	osArgs = []string{"app", "--no-level"}
+	_, _, err = cliargs.ParseFor(osArgs, &options)
+	assert.Nil(t, err)
+	assert.Equal(t, options.Verbose, 0)
+	assert.Equal(t, options.Level, uint(0))
+}
+
+func TestParseFor_decrements(t *testing.T) {
+	type MyOptions struct {
+		Verbose int  `optcfg:"+verbose,v"`
+		Quiet   bool `optcfg:"quiet,q" optdecrements:"verbose"`
+	}
+
+	options := MyOptions{}
+	optCfgs, err := cliargs.MakeOptCfgsFor(&options)
+	assert.Nil(t, err)
+	assert.Equal(t, optCfgs[1].Name, "quiet")
+	assert.Equal(t, optCfgs[1].Decrements, "verbose")
+	assert.False(t, optCfgs[1].HasArg)
+
+	osArgs := []string{"app", "-vvv", "-q"}
+	cmd, _, err := cliargs.ParseFor(osArgs, &options)
+	assert.Nil(t, err)
+	assert.Equal(t, options.Verbose, 2)
+	assert.False(t, options.Quiet)
+	assert.False(t, cmd.HasOpt("quiet"))
+
+	options = MyOptions{}
+	osArgs = []string{"app", "-qq"}
+	_, _, err = cliargs.ParseFor(osArgs, &options)
+	assert.Nil(t, err)
+	assert.Equal(t, options.Verbose, -2)
+}
+
+func TestParseFor_counterNotInteger(t *testing.T) {
+	type MyOptions struct {
+		Verbose string `optcfg:"+verbose"`
+	}
+
+	options := MyOptions{}
+	_, err := cliargs.MakeOptCfgsFor(&options)
+	switch e := err.(type) {
+	case cliargs.IllegalOptionType:
+		assert.Equal(t, e.Option, "verbose")
+		assert.Equal(t, e.Field, "Verbose")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseFor_optionalArg(t *testing.T) {
+	type MyOptions struct {
+		Color string `optcfg:"color,c=never" optimplicit:"always" optarg:"<when>"`
+		Jobs  int    `optcfg:"jobs,j=1" optimplicit:""`
+		Debug bool   `optcfg:"debug" optimplicit:"true"`
+	}
+
+	options := MyOptions{}
+	optCfgs, err := cliargs.MakeOptCfgsFor(&options)
+	assert.Nil(t, err)
+	assert.True(t, optCfgs[0].IsArgOptional)
+	assert.Equal(t, optCfgs[0].ImplicitArg, "always")
+	assert.True(t, optCfgs[1].IsArgOptional)
+	assert.Equal(t, optCfgs[1].ImplicitArg, "")
+	assert.False(t, optCfgs[2].IsArgOptional)
+
+	osArgs := []string{"app", "-c", "file.txt", "--jobs=4"}
+	cmd, _, err := cliargs.ParseFor(osArgs, &options)
+	assert.Nil(t, err)
+	assert.Equal(t, options.Color, "always")
+	assert.Equal(t, options.Jobs, 4)
+	assert.Equal(t, cmd.Args(), []string{"file.txt"})
+
+	options = MyOptions{}
+	_, _, err = cliargs.ParseFor([]string{"app"}, &options)
+	assert.Nil(t, err)
+	assert.Equal(t, options.Color, "never")
+	assert.Equal(t, options.Jobs, 1)
+}
+
+func TestParseFor_arity(t *testing.T) {
+	type Point struct {
+		X, Y int
+	}
+	type MyOptions struct {
+		Point  Point       `optcfg:"point,p"`
+		Range  [2]float64  `optcfg:"range=[0,1]"`
+		Rename [][2]string `optcfg:"rename"`
+		Points []Point     `optcfg:"add"`
+	}
+
+	options := MyOptions{}
+	optCfgs, err := cliargs.MakeOptCfgsFor(&options)
+	assert.Nil(t, err)
+	assert.Equal(t, optCfgs[0].Arity, 2)
+	assert.False(t, optCfgs[0].IsArray)
+	assert.Equal(t, optCfgs[1].Arity, 2)
+	assert.Equal(t, optCfgs[1].Default, []string{"0", "1"})
+	assert.Equal(t, optCfgs[2].Arity, 2)
+	assert.True(t, optCfgs[2].IsArray)
+	assert.Equal(t, optCfgs[3].Arity, 2)
+	assert.True(t, optCfgs[3].IsArray)
+
+	osArgs := []string{"app", "-p", "3", "-4", "--rename", "a", "b",
+		"--rename=c", "d", "--add", "1", "2", "--add", "5", "6", "x"}
+	cmd, _, err := cliargs.ParseFor(osArgs, &options)
+	assert.Nil(t, err)
+	assert.Equal(t, options.Point, Point{X: 3, Y: -4})
+	assert.Equal(t, options.Range, [2]float64{0, 1})
+	assert.Equal(t, options.Rename, [][2]string{{"a", "b"}, {"c", "d"}})
+	assert.Equal(t, options.Points, []Point{{1, 2}, {5, 6}})
+	assert.Equal(t, cmd.Args(), []string{"x"})
+
+	options = MyOptions{}
+	_, _, err = cliargs.ParseFor([]string{"app", "-p", "3", "y"}, &options)
+	switch e := err.(type) {
+	case cliargs.FailToParseInt:
+		assert.Equal(t, e.Option, "point")
+		assert.Equal(t, e.Field, "Point")
+		assert.Equal(t, e.Input, "y")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseFor_stopAtFirstArg(t *testing.T) {
+	type MyOptions struct {
+		Verbose bool   `optcfg:"verbose,v"`
+		Context string `optcfg:"context"`
+	}
+
+	options := MyOptions{}
+	osArgs := []string{"mytool", "--context=dev", "exec", "kubectl", "-v"}
+	cmd, _, err := cliargs.ParseFor(osArgs, &options, cliargs.WithStopAtFirstArg())
+	assert.Nil(t, err)
+	assert.False(t, options.Verbose)
+	assert.Equal(t, options.Context, "dev")
+	assert.Equal(t, cmd.Args(), []string{"exec", "kubectl", "-v"})
+}
+
+func TestParseFor_requiredOpt(t *testing.T) {
+	type MyOptions struct {
+		Output string `optcfg:"!output,o"`
+		Level  int    `optcfg:"!level=3"`
+		Color  bool   `optcfg:"![no-]color"`
+	}
+
+	options := MyOptions{}
+	optCfgs, err := cliargs.MakeOptCfgsFor(&options)
+	assert.Nil(t, err)
+	assert.Equal(t, optCfgs[0].Name, "output")
+	assert.True(t, optCfgs[0].IsRequired)
+	assert.Equal(t, optCfgs[1].Name, "level")
+	assert.True(t, optCfgs[1].IsRequired)
+	assert.Equal(t, optCfgs[2].Name, "color")
+	assert.True(t, optCfgs[2].IsRequired)
+	assert.True(t, optCfgs[2].IsNegatable)
+
+	osArgs := []string{"app", "--color"}
+	_, _, err = cliargs.ParseFor(osArgs, &options)
+	assert.Equal(t, err.Error(),
+		"MissingRequiredOption{Option:output,Aliases:[o]}")
+
+	osArgs = []string{"app", "-o", "x", "--no-color"}
+	_, _, err = cliargs.ParseFor(osArgs, &options)
+	assert.Nil(t, err)
+	assert.Equal(t, options.Output, "x")
+	assert.Equal(t, options.Level, 3)
+	assert.False(t, options.Color)
+}
+
+func TestParseFor_choices(t *testing.T) {
+	type MyOptions struct {
+		Format string   `optcfg:"format,f=json" optchoices:"json,yaml,table"`
+		Levels []string `optcfg:"level" optchoices:"debug,info"`
+		Quiet  bool     `optcfg:"quiet" optchoices:"yes,no"`
+	}
+
+	options := MyOptions{}
+	optCfgs, err := cliargs.MakeOptCfgsFor(&options)
+	assert.Nil(t, err)
+	assert.Equal(t, optCfgs[0].Choices, []string{"json", "yaml", "table"})
+	assert.Equal(t, optCfgs[1].Choices, []string{"debug", "info"})
+	assert.Nil(t, optCfgs[2].Choices)
+
+	osArgs := []string{"app", "-f", "table", "--level", "info"}
+	_, _, err = cliargs.ParseFor(osArgs, &options)
+	assert.Nil(t, err)
+	assert.Equal(t, options.Format, "table")
+	assert.Equal(t, options.Levels, []string{"info"})
+
+	osArgs = []string{"app", "-f", "csv"}
+	_, _, err = cliargs.ParseFor(osArgs, &options)
+	assert.Equal(t, err.Error(),
+		"OptionArgIsNotChoice{Option:format,OptArg:csv,Choices:[json yaml table]}")
+}
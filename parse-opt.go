@@ -0,0 +1,177 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargs
+
+// ParseOpt is a function type which changes the behavior of parsing command
+// line arguments.
+// The functions which create ParseOpt are named WithXxx, and their results
+// can be given to Parse, ParseWith, ParseFor, ParseCmd, and CompleteArgs as
+// variadic arguments.
+// Every behavior changed by ParseOpt is opt-in, so the default behavior is
+// same as Parse function.
+type ParseOpt func(*parseCfg)
+
+// parseCfg is a structure which holds the behaviors of parsing.
+// untilFirstArg is not changed by ParseOpt but is set by ParseCmd to stop
+// parsing at a sub command name.
+// argCfgs and constraints are set by WithArgCfgs and WithConstraints, and
+// are replaced with CmdCfg#ArgCfgs and CmdCfg#Constraints by ParseCmd.
+// digitOpts and numberOpt are not changed by ParseOpt either but are set from
+// option configurations: digitOpts are the digits which are configured as
+// short options, and numberOpt is the name of the number option.
+type parseCfg struct {
+	untilFirstArg     bool
+	attachedShortArgs bool
+	longOptAbbrev     bool
+	boolOptArgs       bool
+	negativeNumbers   bool
+	stopAtFirstArg    bool
+	responseFiles     bool
+	argCfgs           []ArgCfg
+	constraints       []OptConstraint
+	digitOpts         string
+	numberOpt         string
+}
+
+func newParseCfg(parseOpts []ParseOpt) parseCfg {
+	var pc parseCfg
+	for _, opt := range parseOpts {
+		opt(&pc)
+	}
+	return pc
+}
+
+// WithAttachedShortArgs is a function which creates a ParseOpt to take the
+// rest of a short option cluster as the option argument of a short option
+// which takes an option argument, in the same way as POSIX getopt.
+// For example, if the option "o" takes an option argument, "-ofoo" is parsed
+// as the option "o" with the option argument "foo", and "-vofoo" is parsed as
+// the option "v" and the option "o" with the option argument "foo".
+// Without this ParseOpt, "-ofoo" is parsed as a cluster of the options "o",
+// "f", and "o".
+func WithAttachedShortArgs() ParseOpt {
+	return func(pc *parseCfg) {
+		pc.attachedShortArgs = true
+	}
+}
+
+// WithLongOptAbbrev is a function which creates a ParseOpt to accept an
+// abbreviated long option, which is a unique prefix of the names and aliases
+// of the option configurations, in the same way as GNU getopt_long.
+// For example, "--verb" is parsed as "--verbose" if no other option starts
+// with "verb".
+// An option which matches a name or an alias exactly is always prioritized
+// over abbreviations.
+// If an abbreviated long option matches multiple options, the parsing returns
+// AmbiguousOption error.
+func WithLongOptAbbrev() ParseOpt {
+	return func(pc *parseCfg) {
+		pc.longOptAbbrev = true
+	}
+}
+
+// WithBoolOptArgs is a function which creates a ParseOpt to accept an option
+// argument attached with "=" to an option which takes no option argument
+// (.HasArg = false), like --dry-run=false.
+// The option argument is one of true, false, yes, no, on, off, 1, and 0, and
+// is case-insensitive.
+// If the option argument is true, yes, on, or 1, the option is same as the
+// one given without an option argument.
+// If the option argument is false, no, off, or 0, the option is treated as
+// same as a negated option. (See OptCfg#IsNegatable.)
+// If the option argument is none of them, the parsing returns
+// OptionArgIsNotBool error.
+func WithBoolOptArgs() ParseOpt {
+	return func(pc *parseCfg) {
+		pc.boolOptArgs = true
+	}
+}
+
+// WithNegativeNumbers is a function which creates a ParseOpt to take a command
+// line argument which starts with "-" and is a number, like -5, -3.14, or
+// -1e3, as a command argument instead of short options.
+// For example, "calc -5 3" is parsed as the command arguments "-5" and "3".
+// An option which takes an option argument can take a negative number as the
+// following command line argument, like --offset -5, regardless of this
+// ParseOpt.
+// This ParseOpt is ignored if the option configurations have an option of
+// which name or alias is a digit, like "1", or an option of which IsNumberOpt
+// is true, so as not to be ambiguous.
+func WithNegativeNumbers() ParseOpt {
+	return func(pc *parseCfg) {
+		pc.negativeNumbers = true
+	}
+}
+
+// WithStopAtFirstArg is a function which creates a ParseOpt to stop parsing
+// options at the first command argument, in the same way as POSIX getopt with
+// POSIXLY_CORRECT environment variable or an optstring starting with "+".
+// The first command argument and all the following command line arguments are
+// taken as command arguments verbatim, even if they are in option format or
+// are "--".
+// For example, "mytool -v exec kubectl -n foo" is parsed as the option "v"
+// and the command arguments "exec", "kubectl", "-n", and "foo".
+// For a command which has sub commands, this ParseOpt affects only the
+// command arguments of the last sub command, because the parsing of a command
+// always stops at its sub command name.
+func WithStopAtFirstArg() ParseOpt {
+	return func(pc *parseCfg) {
+		pc.stopAtFirstArg = true
+	}
+}
+
+// WithResponseFiles is a function which creates a ParseOpt to expand a
+// command line argument which starts with "@", like @args.txt, to the tokens
+// in the file before parsing, in the same way as javac and gcc.
+// The tokens in a response file are separated by white spaces and line breaks,
+// and can be quoted with single or double quotes as in a shell.
+// A backslash outside quotes escapes the next character, so a token including
+// backslashes, like a Windows path C:\dir\file, needs to be quoted with single
+// quotes.
+// A line starting with "#" is a comment.
+// A response file can include other response files, and @- reads standard
+// input.
+// The command line arguments after "--" are not expanded.
+// If a response file cannot be read, includes itself, or has an unclosed
+// quotation, the parsing returns ResponseFileError which has the file path and
+// the line number.
+func WithResponseFiles() ParseOpt {
+	return func(pc *parseCfg) {
+		pc.responseFiles = true
+	}
+}
+
+// WithArgCfgs is a function which creates a ParseOpt to check command
+// arguments with command argument configurations.
+// (See ArgCfg type.)
+// If command arguments are fewer than the required command arguments, the
+// parsing returns MissingArg error, and if they are more than the command
+// argument configurations accept, the parsing returns ExtraArg error.
+// If no command argument configuration is given, no command argument is
+// accepted.
+// This ParseOpt is ignored by ParseCmd, which uses ArgCfgs field of CmdCfg
+// instead.
+func WithArgCfgs(argCfgs ...ArgCfg) ParseOpt {
+	if argCfgs == nil {
+		argCfgs = []ArgCfg{}
+	}
+	return func(pc *parseCfg) {
+		pc.argCfgs = argCfgs
+	}
+}
+
+// WithConstraints is a function which creates a ParseOpt to apply option
+// constraints after collecting options. (See OptConstraint type.)
+// The options implied by the constraints are registered before the event
+// handlers of option configurations are called, and then if the constraints
+// are not satisfied, the parsing returns OptionsAreExclusive or
+// OptionRequiresOption error.
+// This ParseOpt is ignored by ParseCmd, which uses Constraints field of CmdCfg
+// instead.
+func WithConstraints(constraints ...OptConstraint) ParseOpt {
+	return func(pc *parseCfg) {
+		pc.constraints = constraints
+	}
+}
@@ -7,6 +7,8 @@ package cliargs
 import (
 	"fmt"
 	"path"
+	"strconv"
+	"strings"
 )
 
 // ConfigIsArrayButHasNoArg is an error which indicates that an option
@@ -27,6 +29,77 @@ func (e ConfigHasDefaultButHasNoArg) Error() string {
 	return fmt.Sprintf("ConfigHasDefaultButHasNoArg{Option:%s}", e.Option)
 }
 
+// ConfigIsNegatableButHasArg is an error which indicates that an option
+// configuration contradicts that the option can be negated with "--no-"
+// (.IsNegatable = true) but must have option argument (.HasArg = true).
+type ConfigIsNegatableButHasArg struct{ Option string }
+
+func (e ConfigIsNegatableButHasArg) Error() string {
+	return fmt.Sprintf("ConfigIsNegatableButHasArg{Option:%s}", e.Option)
+}
+
+// ConfigIsCounterButHasArg is an error which indicates that an option
+// configuration contradicts that the option counts its occurrences
+// (.IsCounter = true or .Decrements != "") but must have option argument
+// (.HasArg = true).
+type ConfigIsCounterButHasArg struct{ Option string }
+
+func (e ConfigIsCounterButHasArg) Error() string {
+	return fmt.Sprintf("ConfigIsCounterButHasArg{Option:%s}", e.Option)
+}
+
+// ConfigDecrementsNonCounter is an error which indicates that an option
+// configuration decrements an option (.Decrements) which is not configured or
+// is not a counter (.IsCounter = false).
+type ConfigDecrementsNonCounter struct{ Option, Decrements string }
+
+func (e ConfigDecrementsNonCounter) Error() string {
+	return fmt.Sprintf("ConfigDecrementsNonCounter{Option:%s,Decrements:%s}",
+		e.Option, e.Decrements)
+}
+
+// ConfigIsArgOptionalButHasNoArg is an error which indicates that an option
+// configuration contradicts that the option argument is optional
+// (.IsArgOptional = true) but must have no option argument (.HasArg = false).
+type ConfigIsArgOptionalButHasNoArg struct{ Option string }
+
+func (e ConfigIsArgOptionalButHasNoArg) Error() string {
+	return fmt.Sprintf("ConfigIsArgOptionalButHasNoArg{Option:%s}", e.Option)
+}
+
+// ConfigHasInvalidArity is an error which indicates that the number of option
+// arguments per occurrence (.Arity) of an option configuration is negative, or
+// is greater than one though the option takes no option argument
+// (.HasArg = false) or an optional option argument (.IsArgOptional = true), or
+// though the number of the default values (.Default) is not a multiple of it.
+type ConfigHasInvalidArity struct {
+	Option string
+	Arity  int
+}
+
+func (e ConfigHasInvalidArity) Error() string {
+	return fmt.Sprintf("ConfigHasInvalidArity{Option:%s,Arity:%d}",
+		e.Option, e.Arity)
+}
+
+// ConfigIsNumberOptButHasNoArg is an error which indicates that an option
+// configuration contradicts that the option takes a number given like -20
+// (.IsNumberOpt = true) but must have no option argument (.HasArg = false).
+type ConfigIsNumberOptButHasNoArg struct{ Option string }
+
+func (e ConfigIsNumberOptButHasNoArg) Error() string {
+	return fmt.Sprintf("ConfigIsNumberOptButHasNoArg{Option:%s}", e.Option)
+}
+
+// ConfigHasChoicesButHasNoArg is an error which indicates that an option
+// configuration contradicts that the option argument is limited to Choices but
+// the option must have no option argument (.HasArg = false).
+type ConfigHasChoicesButHasNoArg struct{ Option string }
+
+func (e ConfigHasChoicesButHasNoArg) Error() string {
+	return fmt.Sprintf("ConfigHasChoicesButHasNoArg{Option:%s}", e.Option)
+}
+
 // UnconfiguredOption is an error which indicates that there is no
 // configuration about the input option.
 type UnconfiguredOption struct{ Option string }
@@ -44,6 +117,21 @@ func (e OptionNeedsArg) Error() string {
 	return fmt.Sprintf("OptionNeedsArg{Option:%s}", e.Option)
 }
 
+// OptionNeedsMoreArgs is an error which indicates that an option is input with
+// fewer option arguments than the number of option arguments per occurrence
+// (.Arity) of its option configuration.
+// Given is the number of the option arguments which are input.
+type OptionNeedsMoreArgs struct {
+	Option string
+	Arity  int
+	Given  int
+}
+
+func (e OptionNeedsMoreArgs) Error() string {
+	return fmt.Sprintf("OptionNeedsMoreArgs{Option:%s,Arity:%d,Given:%d}",
+		e.Option, e.Arity, e.Given)
+}
+
 // OptionTakesNoArg is an error which indicates that an option isinput with
 // an option argument though its option configuration does not accept option
 // arguments (.HasArg = false).
@@ -53,6 +141,57 @@ func (e OptionTakesNoArg) Error() string {
 	return fmt.Sprintf("OptionTakesNoArg{Option:%s}", e.Option)
 }
 
+// OptionArgIsNotBool is an error which indicates that an option argument
+// attached to an option which takes no option argument is not a boolean
+// value. (See WithBoolOptArgs function.)
+type OptionArgIsNotBool struct{ Option, OptArg string }
+
+func (e OptionArgIsNotBool) Error() string {
+	return fmt.Sprintf("OptionArgIsNotBool{Option:%s,OptArg:%s}",
+		e.Option, e.OptArg)
+}
+
+// AmbiguousOption is an error which indicates that an abbreviated long option
+// matches the prefixes of multiple options.
+// Candidates are the names or aliases of the matched options.
+type AmbiguousOption struct {
+	Option     string
+	Candidates []string
+}
+
+func (e AmbiguousOption) Error() string {
+	return fmt.Sprintf("AmbiguousOption{Option:%s,Candidates:[%s]}",
+		e.Option, strings.Join(e.Candidates, " "))
+}
+
+// MissingRequiredOption is an error which indicates that an option which is
+// required (.IsRequired = true) is not given in command line arguments and has
+// no default value.
+// Aliases are the aliases of the option.
+type MissingRequiredOption struct {
+	Option  string
+	Aliases []string
+}
+
+func (e MissingRequiredOption) Error() string {
+	return fmt.Sprintf("MissingRequiredOption{Option:%s,Aliases:[%s]}",
+		e.Option, strings.Join(e.Aliases, " "))
+}
+
+// OptionArgIsNotChoice is an error which indicates that an option argument is
+// not any of the allowed values of the option (.Choices).
+// Choices are the allowed values of the option.
+type OptionArgIsNotChoice struct {
+	Option  string
+	OptArg  string
+	Choices []string
+}
+
+func (e OptionArgIsNotChoice) Error() string {
+	return fmt.Sprintf("OptionArgIsNotChoice{Option:%s,OptArg:%s,Choices:[%s]}",
+		e.Option, e.OptArg, strings.Join(e.Choices, " "))
+}
+
 // OptionIsNotArray is an error which indicates that an option is input with
 // an option argument multiple times though its option configuration specifies
 // the option is not an array (.IsArray = false).
@@ -64,13 +203,21 @@ func (e OptionIsNotArray) Error() string {
 
 const anyOption = "*"
 
+const negPrefix = "no-"
+
+var negatedArgs = []string{"false"}
+
 // OptCfg is a structure that represents an option configuration.
 // An option configuration consists of fields: Name, Aliases, HasArg,
-// IsArray, Default, OnParsed, Desc, and ArgHelp.
+// IsArray, Default, OnParsed, Desc, ArgHelp, IsGlobal, CompHint, OnComplete,
+// IsNegatable, IsCounter, Decrements, IsArgOptional, ImplicitArg, Arity,
+// IsNumberOpt, IsRequired, and Choices.
 //
 // Name is the option name and Aliases are the another names.
 // Options given by those names in command line arguments are all registered to
 // Args with the Name.
+// A digit, like "1", can be used as a short option name only if it is
+// configured, and can be combined with other short options, like -9v.
 //
 // HasArg and IsArray are flags which allows the option to take option
 // arguments.
@@ -94,15 +241,110 @@ const anyOption = "*"
 //
 // ArgHelp is a display at a argument position of this option in a help text.
 // This string is for a display like: -o, --option <value>.
+//
+// IsGlobal is a flag which allows the option to be specified also after the
+// names of sub commands.
+// This flag is used only by ParseCmd function, and an option of which this
+// flag is true is accepted at the command where it is configured and at all
+// sub commands of the command.
+// The option arguments of a global option are registered once to the Cmd of
+// the command where it is configured.
+//
+// CompHint is a hint which indicates what kind of values are offered as
+// candidates when completing the option argument in a shell.
+// If this field is COMP_NONE, which is the zero value, no candidate is offered.
+//
+// OnComplete is the field for the event handler which is called when the
+// option argument is completed dynamically by the hidden "__complete" sub
+// command. (See RunCompletion function.)
+// This handler receives a Cmd which is parsed from the command line arguments
+// before the completed one, and the prefix of the option argument, and
+// returns candidates of the option argument.
+// If this field is not nil, CompHint is ignored.
+//
+// IsNegatable is a flag which allows the option to be negated by "--no-"
+// followed by its name or its long alias, like --no-color.
+// This flag is valid only for an option which takes no option argument.
+// If the option and its negation are given multiple times, the last one
+// decides the value.
+// A negated option is not registered to Cmd, and OnParsed of the option
+// receives an array which has only "false".
+//
+// IsCounter is a flag which makes the option count its occurrences, like -vvv.
+// This flag is valid only for an option which takes no option argument.
+// The number of the occurrences is registered to Cmd as the only option
+// argument in decimal, like "3".
+// If a counter option is negated, its number is reset to "0".
+//
+// Decrements is the field to specify the name of a counter option which is
+// decremented by this option, like -q for -v.
+// An option of which this field is not empty is not registered to Cmd by
+// itself, and the number of the counter option can be negative.
+//
+// IsArgOptional is a flag which makes the option argument optional.
+// This flag is valid only for an option which takes option argument.
+// An optional option argument can be given only with "=", like --color=always,
+// so the command line argument following the option, like --color file.txt, is
+// not taken as its option argument.
+// ImplicitArg is the option argument for when the option is given without an
+// option argument.
+// (ImplicitArg is different from Default which is the option argument for when
+// the option is not given.)
+//
+// Arity is the field to specify the exact number of option arguments which the
+// option takes per occurrence, like --point X Y.
+// If this field is greater than one, the option takes the following command
+// line arguments as many as this field, and the option arguments of each
+// occurrence are registered to Cmd as a group. (See Cmd#OptArgGroups.)
+// This field is valid only for an option which takes a mandatory option
+// argument, and the number of Default has to be a multiple of this field.
+// If this field is zero or one, the option takes one option argument per
+// occurrence as usual.
+//
+// IsNumberOpt is a flag which makes a command line argument which consists of
+// "-" and digits, like -20 of head command, this option with the digits as its
+// option argument.
+// This flag is valid only for an option which takes option argument.
+// If a single digit is also configured as a short option, the digit is
+// prioritized.
+// If multiple option configurations have this flag, the last one is used, and
+// an option of a sub command is prior to a global option of its ancestors.
+//
+// IsRequired is a flag which makes the option mandatory.
+// If the option is not given in command line arguments and has no Default,
+// the parsing returns MissingRequiredOption error.
+// A negated option (--no-name) is regarded as given.
+// This flag is ignored for an option which decrements a counter option.
+//
+// Choices is the field to specify the allowed values of the option argument,
+// like json, yaml, and table.
+// If this field is not empty, every option argument of the option, including
+// ImplicitArg, is checked, and the parsing returns OptionArgIsNotChoice error
+// if it is not any of them. (Default is not checked.)
+// This field is valid only for an option which takes option argument.
+// The choices are shown in a help text if ArgHelp is empty, and are offered as
+// candidates when completing the option argument.
 type OptCfg struct {
-	Name     string
-	Aliases  []string
-	HasArg   bool
-	IsArray  bool
-	Default  []string
-	OnParsed *func([]string) error
-	Desc     string
-	ArgHelp  string
+	Name          string
+	Aliases       []string
+	HasArg        bool
+	IsArray       bool
+	Default       []string
+	OnParsed      *func([]string) error
+	Desc          string
+	ArgHelp       string
+	IsGlobal      bool
+	CompHint      CompHint
+	OnComplete    *func(Cmd, string) []string
+	IsNegatable   bool
+	IsCounter     bool
+	Decrements    string
+	IsArgOptional bool
+	ImplicitArg   string
+	Arity         int
+	IsNumberOpt   bool
+	IsRequired    bool
+	Choices       []string
 }
 
 // ParseWith is a function which parses command line arguments with option
@@ -129,40 +371,151 @@ type OptCfg struct {
 // arguments, this function basically returns UnconfiguredOption error.
 // If you want to allow other options, add an option configuration of which
 // Name is "*" (but HasParam and IsArray of this configuration is ignored).
-func ParseWith(osArgs []string, optCfgs []OptCfg) (Cmd, error) {
+//
+// The behavior of parsing can be changed by ParseOpt(s) which are given as
+// variadic arguments. (See ParseOpt type.)
+func ParseWith(
+	osArgs []string, optCfgs []OptCfg, parseOpts ...ParseOpt,
+) (Cmd, error) {
+	var cmdName string
+	if len(osArgs) > 0 {
+		cmdName = path.Base(osArgs[0])
+	}
+
+	var osArgs1 []string
+	if len(osArgs) > 1 {
+		osArgs1 = osArgs[1:]
+	}
+
+	pc := newParseCfg(parseOpts)
+
+	if pc.responseFiles {
+		var err error
+		osArgs1, err = expandResponseFiles(osArgs1)
+		if err != nil {
+			return Cmd{args: empty}, err
+		}
+	}
+
+	args, opts, _, err := parseWith(osArgs1, optCfgs, nil, pc)
+	if err != nil {
+		return Cmd{args: empty}, err
+	}
+
+	err = checkArgs(pc.argCfgs, args)
+	if err != nil {
+		return Cmd{args: empty}, err
+	}
+
+	err = applyConstraints(pc.constraints, optCfgs, opts)
+	if err != nil {
+		return Cmd{args: empty}, err
+	}
+
+	err = applyOptCfgs(optCfgs, opts)
+	if err != nil {
+		return Cmd{args: empty}, err
+	}
+
+	cmd := Cmd{Name: cmdName, args: args, opts: opts}
+	cmd.arities = arityMap(optCfgs)
+	return cmd, nil
+}
+
+// globalOpts is a structure which holds global option configurations of an
+// ancestor command and the map to which their option arguments are stored.
+type globalOpts struct {
+	optCfgs []OptCfg
+	opts    map[string][]string
+}
+
+func parseWith(
+	osArgs []string,
+	optCfgs []OptCfg,
+	globals []globalOpts,
+	pc parseCfg,
+) ([]string, map[string][]string, int, error) {
+	var args = make([]string, 0)
+	var opts = make(map[string][]string)
+
 	hasAnyOpt := false
+	cfgs := make([]OptCfg, 0, len(optCfgs))
+	stores := make([]map[string][]string, 0, len(optCfgs))
 	cfgMap := make(map[string]int)
-	for i, cfg := range optCfgs {
-		if !cfg.HasArg {
-			if cfg.IsArray {
-				err := ConfigIsArrayButHasNoArg{Option: cfg.Name}
-				return Cmd{args: empty}, err
+	negMap := make(map[string]int)
+
+	for _, g := range globals {
+		for _, cfg := range g.optCfgs {
+			if !cfg.IsGlobal || cfg.Name == anyOption {
+				continue
 			}
-			if cfg.Default != nil {
-				err := ConfigHasDefaultButHasNoArg{Option: cfg.Name}
-				return Cmd{args: empty}, err
+			cfgMap[cfg.Name] = len(cfgs)
+			for _, a := range cfg.Aliases {
+				cfgMap[a] = len(cfgs)
 			}
+			addNegatedNames(negMap, cfg, len(cfgs))
+			cfgs = append(cfgs, cfg)
+			stores = append(stores, g.opts)
+		}
+	}
+
+	for _, cfg := range optCfgs {
+		err := checkOptCfg(cfg)
+		if err != nil {
+			return nil, nil, -1, err
 		}
 		if cfg.Name == anyOption {
 			hasAnyOpt = true
 			continue
 		}
-		cfgMap[cfg.Name] = i
+		cfgMap[cfg.Name] = len(cfgs)
 		for _, a := range cfg.Aliases {
-			cfgMap[a] = i
+			cfgMap[a] = len(cfgs)
 		}
+		addNegatedNames(negMap, cfg, len(cfgs))
+		cfgs = append(cfgs, cfg)
+		stores = append(stores, opts)
 	}
 
-	var takeArg = func(opt string) bool {
-		i, exists := cfgMap[opt]
-		if exists {
-			return optCfgs[i].HasArg
+	for _, cfg := range optCfgs {
+		if len(cfg.Decrements) > 0 {
+			i, exists := cfgMap[cfg.Decrements]
+			if !exists || !cfgs[i].IsCounter {
+				return nil, nil, -1, ConfigDecrementsNonCounter{
+					Option: cfg.Name, Decrements: cfg.Decrements}
+			}
 		}
-		return false
 	}
 
-	var args = make([]string, 0)
-	var opts = make(map[string][]string)
+	pc.digitOpts, pc.numberOpt = digitOpts(cfgs)
+
+	// Negative numbers are not taken as command arguments if there are digit
+	// options or a number option, because they are ambiguous.
+	if len(pc.digitOpts) > 0 || len(pc.numberOpt) > 0 {
+		pc.negativeNumbers = false
+	}
+
+	var turnOffOpt = func(i int) {
+		cfg := cfgs[i]
+		if len(cfg.Decrements) > 0 {
+			return
+		}
+		if cfg.IsCounter {
+			stores[i][cfg.Name] = []string{"0"}
+			return
+		}
+		// A nil entry marks the option as turned off, and it is resolved in
+		// applyOptCfgs.
+		stores[i][cfg.Name] = nil
+	}
+
+	var takeArg = func(opt string) int {
+		i, exists := cfgMap[opt]
+		if exists && cfgs[i].HasArg && !cfgs[i].IsArgOptional {
+			return arityOf(cfgs[i])
+		}
+		return 0
+	}
 
 	var collectArg = func(a ...string) error {
 		args = append(args, a...)
@@ -171,6 +524,13 @@ func ParseWith(osArgs []string, optCfgs []OptCfg) (Cmd, error) {
 	var collectOpt = func(name string, a ...string) error {
 		i, exists := cfgMap[name]
 		if !exists {
+			if j, isNeg := negMap[name]; isNeg {
+				if len(a) > 0 {
+					return OptionTakesNoArg{Option: cfgs[j].Name}
+				}
+				turnOffOpt(j)
+				return nil
+			}
 			if !hasAnyOpt {
 				return UnconfiguredOption{Option: name}
 			}
@@ -183,61 +543,275 @@ func ParseWith(osArgs []string, optCfgs []OptCfg) (Cmd, error) {
 			return nil
 		}
 
-		cfg := optCfgs[i]
+		cfg := cfgs[i]
+		store := stores[i]
+
 		if !cfg.HasArg {
 			if len(a) > 0 {
-				return OptionTakesNoArg{Option: cfg.Name}
+				if !pc.boolOptArgs {
+					return OptionTakesNoArg{Option: cfg.Name}
+				}
+				b, ok := parseBoolOptArg(a[0])
+				if !ok {
+					return OptionArgIsNotBool{Option: cfg.Name, OptArg: a[0]}
+				}
+				if !b {
+					turnOffOpt(i)
+					return nil
+				}
+				a = nil
 			}
 		} else {
 			if len(a) == 0 {
-				return OptionNeedsArg{Option: cfg.Name}
+				if !cfg.IsArgOptional {
+					return OptionNeedsArg{Option: cfg.Name}
+				}
+				a = []string{cfg.ImplicitArg}
+			} else if len(a) < cfg.Arity {
+				return OptionNeedsMoreArgs{
+					Option: cfg.Name, Arity: cfg.Arity, Given: len(a)}
 			}
+			if len(cfg.Choices) > 0 {
+				for _, s := range a {
+					if !isChoice(s, cfg.Choices) {
+						return OptionArgIsNotChoice{
+							Option: cfg.Name, OptArg: s, Choices: cfg.Choices}
+					}
+				}
+			}
+		}
+
+		if len(cfg.Decrements) > 0 {
+			j := cfgMap[cfg.Decrements]
+			countOpt(stores[j], cfgs[j].Name, -1)
+			return nil
+		}
+		if cfg.IsCounter {
+			countOpt(store, cfg.Name, 1)
+			return nil
 		}
 
-		arr := opts[cfg.Name]
+		arr := store[cfg.Name]
 		if arr == nil {
 			arr = empty
 		}
 		arr = append(arr, a...)
 
 		if !cfg.IsArray {
-			if len(arr) > 1 {
+			if len(arr) > arityOf(cfg) {
 				return OptionIsNotArray{Option: cfg.Name}
 			}
 		}
 
-		opts[cfg.Name] = arr
+		store[cfg.Name] = arr
 		return nil
 	}
 
-	var cmdName string
-	if len(osArgs) > 0 {
-		cmdName = path.Base(osArgs[0])
+	var resolveLongOpt func(string) (string, error)
+	if pc.longOptAbbrev {
+		// A global option shadowed by an option which has the same name is not a
+		// candidate of an abbreviation.
+		abbrevCfgs := make([]OptCfg, 0, len(cfgs))
+		for i, cfg := range cfgs {
+			if cfgMap[cfg.Name] == i {
+				abbrevCfgs = append(abbrevCfgs, cfg)
+			}
+		}
+		resolveLongOpt = func(name string) (string, error) {
+			if _, exists := cfgMap[name]; exists {
+				return name, nil
+			}
+			if _, exists := negMap[name]; exists {
+				return name, nil
+			}
+			return resolveLongOptAbbrev(name, abbrevCfgs)
+		}
 	}
 
-	var osArgs1 []string
-	if len(osArgs) > 1 {
-		osArgs1 = osArgs[1:]
+	iArg, err := parseArgs(
+		osArgs, collectArg, collectOpt, takeArg, resolveLongOpt, pc)
+	if err != nil {
+		return nil, nil, -1, err
 	}
 
-	err := parseArgs(osArgs1, collectArg, collectOpt, takeArg)
-	if err != nil {
-		return Cmd{args: empty}, err
+	return args, opts, iArg, nil
+}
+
+// digitOpts is a function which returns the digits which are configured as
+// short options and the name of the number option.
+func digitOpts(optCfgs []OptCfg) (string, string) {
+	var digits, numberOpt string
+	for _, cfg := range optCfgs {
+		for _, s := range append([]string{cfg.Name}, cfg.Aliases...) {
+			if len(s) == 1 && isDigits(s) {
+				digits += s
+			}
+		}
+		if cfg.IsNumberOpt {
+			numberOpt = cfg.Name
+		}
+	}
+	return digits, numberOpt
+}
+
+// arityOf is a function which returns the number of option arguments which
+// the option takes per occurrence.
+func arityOf(cfg OptCfg) int {
+	if cfg.Arity > 1 {
+		return cfg.Arity
+	}
+	return 1
+}
+
+// isChoice is a function which returns true if the option argument is one of
+// the allowed values of the option.
+func isChoice(s string, choices []string) bool {
+	for _, c := range choices {
+		if s == c {
+			return true
+		}
+	}
+	return false
+}
+
+func arityMap(optCfgs []OptCfg) map[string]int {
+	var arities map[string]int
+	for _, cfg := range optCfgs {
+		if cfg.Arity > 1 {
+			if arities == nil {
+				arities = make(map[string]int)
+			}
+			arities[cfg.Name] = cfg.Arity
+		}
+	}
+	return arities
+}
+
+// countOpt is a function which adds n to the number of the occurrences of a
+// counter option.
+func countOpt(store map[string][]string, name string, n int) {
+	c := 0
+	if arr := store[name]; len(arr) > 0 {
+		c, _ = strconv.Atoi(arr[0])
+	}
+	store[name] = []string{strconv.Itoa(c + n)}
+}
+
+// parseBoolOptArg is a function which parses an option argument of an option
+// which takes no option argument as a boolean value.
+// The second returned value is false if the option argument is invalid.
+func parseBoolOptArg(s string) (bool, bool) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "on", "1":
+		return true, true
+	case "false", "no", "off", "0":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// addNegatedNames is a function which registers the negated names of the
+// name and the long aliases of a negatable option configuration.
+func addNegatedNames(negMap map[string]int, cfg OptCfg, i int) {
+	if !cfg.IsNegatable {
+		return
+	}
+	for _, s := range append([]string{cfg.Name}, cfg.Aliases...) {
+		if len(s) > 1 {
+			negMap[negPrefix+s] = i
+		}
+	}
+}
+
+// resolveLongOptAbbrev is a function which returns the name of the option
+// configuration of which name or alias starts with the abbreviated name.
+// If no option configuration matches, this function returns the abbreviated
+// name as it is, and if multiple option configurations match, this function
+// returns AmbiguousOption error.
+func resolveLongOptAbbrev(abbrev string, optCfgs []OptCfg) (string, error) {
+	var name string
+	var cands []string
+
+	for _, cfg := range optCfgs {
+		for _, s := range append([]string{cfg.Name}, cfg.Aliases...) {
+			if strings.HasPrefix(s, abbrev) {
+				name = cfg.Name
+				cands = append(cands, s)
+				break
+			}
+		}
+	}
+
+	switch len(cands) {
+	case 0:
+		return abbrev, nil
+	case 1:
+		return name, nil
+	default:
+		return "", AmbiguousOption{Option: abbrev, Candidates: cands}
+	}
+}
+
+func checkOptCfg(cfg OptCfg) error {
+	if cfg.Arity < 0 || (cfg.Arity > 1 &&
+		(!cfg.HasArg || cfg.IsArgOptional || len(cfg.Default)%cfg.Arity != 0)) {
+		return ConfigHasInvalidArity{Option: cfg.Name, Arity: cfg.Arity}
+	}
+	if cfg.HasArg {
+		if cfg.IsNegatable {
+			return ConfigIsNegatableButHasArg{Option: cfg.Name}
+		}
+		if cfg.IsCounter || len(cfg.Decrements) > 0 {
+			return ConfigIsCounterButHasArg{Option: cfg.Name}
+		}
+	} else {
+		if cfg.IsArray {
+			return ConfigIsArrayButHasNoArg{Option: cfg.Name}
+		}
+		if cfg.IsArgOptional {
+			return ConfigIsArgOptionalButHasNoArg{Option: cfg.Name}
+		}
+		if cfg.IsNumberOpt {
+			return ConfigIsNumberOptButHasNoArg{Option: cfg.Name}
+		}
+		if len(cfg.Choices) > 0 {
+			return ConfigHasChoicesButHasNoArg{Option: cfg.Name}
+		}
+		if cfg.Default != nil {
+			return ConfigHasDefaultButHasNoArg{Option: cfg.Name}
+		}
+	}
+	return nil
+}
+
+func applyOptCfgs(optCfgs []OptCfg, opts map[string][]string) error {
+	// Checks required options before calling event handlers so as not to
+	// apply a part of options.
+	for _, cfg := range optCfgs {
+		if !cfg.IsRequired || len(cfg.Decrements) > 0 || cfg.Default != nil {
+			continue
+		}
+		if _, exists := opts[cfg.Name]; !exists {
+			return MissingRequiredOption{Option: cfg.Name, Aliases: cfg.Aliases}
+		}
 	}
 
 	for _, cfg := range optCfgs {
 		arr, exists := opts[cfg.Name]
-		if !exists && cfg.Default != nil {
+		if exists && arr == nil {
+			delete(opts, cfg.Name)
+			arr = negatedArgs
+		} else if !exists && cfg.Default != nil {
 			arr = cfg.Default
 			opts[cfg.Name] = arr
 		}
 		if cfg.OnParsed != nil {
-			err = (*cfg.OnParsed)(arr)
+			err := (*cfg.OnParsed)(arr)
 			if err != nil {
-				return Cmd{args: empty}, err
+				return err
 			}
 		}
 	}
-
-	return Cmd{Name: cmdName, args: args, opts: opts}, nil
+	return nil
 }
@@ -852,3 +852,889 @@ func TestParseWith_multipleArgs(t *testing.T) {
 	assert.Equal(t, cmd.OptArgs("corge"), []string{"99"})
 	assert.Equal(t, cmd.Args(), []string{"qux", "quux"})
 }
+
+func TestParseWith_attachedShortArgs(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "v"},
+		cliargs.OptCfg{Name: "x"},
+		cliargs.OptCfg{Name: "file", Aliases: []string{"f"}, HasArg: true},
+		cliargs.OptCfg{Name: "n", HasArg: true, IsArray: true},
+	}
+
+	osArgs := []string{"app", "-ffoo", "-n5", "-vxn-1", "-n", "7", "-n=8"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithAttachedShortArgs())
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("v"))
+	assert.True(t, cmd.HasOpt("x"))
+	assert.Equal(t, cmd.OptArgs("file"), []string{"foo"})
+	assert.Equal(t, cmd.OptArgs("n"), []string{"5", "-1", "7", "8"})
+	assert.Equal(t, cmd.Args(), []string{})
+
+	osArgs = []string{"app", "-vxf", "archive.tar", "qux"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs, cliargs.WithAttachedShortArgs())
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("v"))
+	assert.True(t, cmd.HasOpt("x"))
+	assert.Equal(t, cmd.OptArgs("file"), []string{"archive.tar"})
+	assert.Equal(t, cmd.Args(), []string{"qux"})
+
+	osArgs = []string{"app", "-fあいう"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs, cliargs.WithAttachedShortArgs())
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArgs("file"), []string{"あいう"})
+}
+
+func TestParseWith_attachedShortArgs_notOptedIn(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "v"},
+		cliargs.OptCfg{Name: "o", HasArg: true},
+	}
+
+	cmd, err := cliargs.ParseWith([]string{"app", "-ov"}, optCfgs)
+	switch e := err.(type) {
+	case cliargs.OptionNeedsArg:
+		assert.Equal(t, e.Option, "o")
+	default:
+		assert.Fail(t, err.Error())
+	}
+	assert.Equal(t, cmd.Args(), []string{})
+
+	cmd, err = cliargs.ParseWith([]string{"app", "-ov"}, optCfgs,
+		cliargs.WithAttachedShortArgs())
+	assert.Nil(t, err)
+	assert.False(t, cmd.HasOpt("v"))
+	assert.Equal(t, cmd.OptArg("o"), "v")
+}
+
+func TestParseWith_longOptAbbrev(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose"},
+		cliargs.OptCfg{Name: "version"},
+		cliargs.OptCfg{Name: "config", Aliases: []string{"conf-file"}, HasArg: true},
+		cliargs.OptCfg{Name: "c", Aliases: []string{"count"}},
+		cliargs.OptCfg{Name: "ver"},
+	}
+
+	osArgs := []string{"app", "--verb", "--con", "a.yml", "--cou", "--ver", "qux"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithLongOptAbbrev())
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("verbose"))
+	assert.False(t, cmd.HasOpt("version"))
+	assert.Equal(t, cmd.OptArg("config"), "a.yml")
+	assert.True(t, cmd.HasOpt("c"))
+	assert.True(t, cmd.HasOpt("ver"))
+	assert.Equal(t, cmd.Args(), []string{"qux"})
+
+	osArgs = []string{"app", "--conf=b.yml", "--c"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs, cliargs.WithLongOptAbbrev())
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("config"), "b.yml")
+	assert.True(t, cmd.HasOpt("c"))
+}
+
+func TestParseWith_longOptAbbrev_ambiguous(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose"},
+		cliargs.OptCfg{Name: "version", Aliases: []string{"vers"}},
+	}
+
+	cmd, err := cliargs.ParseWith([]string{"app", "--ve"}, optCfgs,
+		cliargs.WithLongOptAbbrev())
+	assert.Equal(t, err.Error(),
+		"AmbiguousOption{Option:ve,Candidates:[verbose version]}")
+	switch e := err.(type) {
+	case cliargs.AmbiguousOption:
+		assert.Equal(t, e.Option, "ve")
+		assert.Equal(t, e.Candidates, []string{"verbose", "version"})
+	default:
+		assert.Fail(t, err.Error())
+	}
+	assert.Equal(t, cmd.Args(), []string{})
+
+	cmd, err = cliargs.ParseWith([]string{"app", "--vers"}, optCfgs,
+		cliargs.WithLongOptAbbrev())
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("version"))
+}
+
+func TestParseWith_longOptAbbrev_notOptedIn(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose"},
+	}
+
+	_, err := cliargs.ParseWith([]string{"app", "--verb"}, optCfgs)
+	switch e := err.(type) {
+	case cliargs.UnconfiguredOption:
+		assert.Equal(t, e.Option, "verb")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	_, err = cliargs.ParseWith([]string{"app", "--verbo"}, optCfgs,
+		cliargs.WithLongOptAbbrev())
+	assert.Nil(t, err)
+
+	_, err = cliargs.ParseWith([]string{"app", "--quiet"}, optCfgs,
+		cliargs.WithLongOptAbbrev())
+	switch e := err.(type) {
+	case cliargs.UnconfiguredOption:
+		assert.Equal(t, e.Option, "quiet")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseWith_negatableOpt(t *testing.T) {
+	var onParsed []string
+	fn := func(a []string) error {
+		onParsed = a
+		return nil
+	}
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name:        "color",
+			Aliases:     []string{"c", "colour"},
+			IsNegatable: true,
+			OnParsed:    &fn,
+		},
+	}
+
+	cmd, err := cliargs.ParseWith([]string{"app", "--no-color"}, optCfgs)
+	assert.Nil(t, err)
+	assert.False(t, cmd.HasOpt("color"))
+	assert.Equal(t, onParsed, []string{"false"})
+
+	cmd, err = cliargs.ParseWith([]string{"app", "--no-color", "-c"}, optCfgs)
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("color"))
+	assert.Equal(t, onParsed, []string{})
+
+	cmd, err = cliargs.ParseWith(
+		[]string{"app", "--color", "--no-colour"}, optCfgs)
+	assert.Nil(t, err)
+	assert.False(t, cmd.HasOpt("color"))
+	assert.Equal(t, onParsed, []string{"false"})
+
+	cmd, err = cliargs.ParseWith([]string{"app"}, optCfgs)
+	assert.Nil(t, err)
+	assert.False(t, cmd.HasOpt("color"))
+	assert.Nil(t, onParsed)
+
+	_, err = cliargs.ParseWith([]string{"app", "--no-c"}, optCfgs)
+	switch e := err.(type) {
+	case cliargs.UnconfiguredOption:
+		assert.Equal(t, e.Option, "no-c")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	_, err = cliargs.ParseWith([]string{"app", "--no-color=x"}, optCfgs)
+	switch e := err.(type) {
+	case cliargs.OptionTakesNoArg:
+		assert.Equal(t, e.Option, "color")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseWith_negatableOpt_notNegatable(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "color"},
+	}
+
+	_, err := cliargs.ParseWith([]string{"app", "--no-color"}, optCfgs)
+	switch e := err.(type) {
+	case cliargs.UnconfiguredOption:
+		assert.Equal(t, e.Option, "no-color")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseWith_negatableOpt_configHasArg(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "color", HasArg: true, IsNegatable: true},
+	}
+
+	_, err := cliargs.ParseWith([]string{"app"}, optCfgs)
+	assert.Equal(t, err.Error(), "ConfigIsNegatableButHasArg{Option:color}")
+	switch e := err.(type) {
+	case cliargs.ConfigIsNegatableButHasArg:
+		assert.Equal(t, e.Option, "color")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseWith_boolOptArgs(t *testing.T) {
+	var onParsed []string
+	fn := func(a []string) error {
+		onParsed = a
+		return nil
+	}
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "dry-run", Aliases: []string{"n"}, OnParsed: &fn},
+	}
+
+	for _, s := range []string{"true", "yes", "on", "1", "TRUE", "Yes"} {
+		osArgs := []string{"app", "--dry-run=" + s}
+		cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithBoolOptArgs())
+		assert.Nil(t, err)
+		assert.True(t, cmd.HasOpt("dry-run"))
+		assert.Equal(t, cmd.OptArgs("dry-run"), []string{})
+		assert.Equal(t, onParsed, []string{})
+	}
+
+	for _, s := range []string{"false", "no", "off", "0", "FALSE", "Off"} {
+		osArgs := []string{"app", "--dry-run", "-n=" + s}
+		cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithBoolOptArgs())
+		assert.Nil(t, err)
+		assert.False(t, cmd.HasOpt("dry-run"))
+		assert.Equal(t, onParsed, []string{"false"})
+	}
+
+	osArgs := []string{"app", "--dry-run=no", "-n"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithBoolOptArgs())
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("dry-run"))
+
+	osArgs = []string{"app", "--dry-run", "false"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs, cliargs.WithBoolOptArgs())
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("dry-run"))
+	assert.Equal(t, cmd.Args(), []string{"false"})
+}
+
+func TestParseWith_boolOptArgs_invalid(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "dry-run"},
+	}
+
+	osArgs := []string{"app", "--dry-run=maybe"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithBoolOptArgs())
+	assert.Equal(t, err.Error(),
+		"OptionArgIsNotBool{Option:dry-run,OptArg:maybe}")
+	switch e := err.(type) {
+	case cliargs.OptionArgIsNotBool:
+		assert.Equal(t, e.Option, "dry-run")
+		assert.Equal(t, e.OptArg, "maybe")
+	default:
+		assert.Fail(t, err.Error())
+	}
+	assert.Equal(t, cmd.Args(), []string{})
+}
+
+func TestParseWith_boolOptArgs_notOptedIn(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "dry-run"},
+	}
+
+	_, err := cliargs.ParseWith([]string{"app", "--dry-run=false"}, optCfgs)
+	switch e := err.(type) {
+	case cliargs.OptionTakesNoArg:
+		assert.Equal(t, e.Option, "dry-run")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseWith_counterOpt(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}, IsCounter: true},
+		cliargs.OptCfg{Name: "quiet", Aliases: []string{"q"}, Decrements: "v"},
+		cliargs.OptCfg{Name: "a"},
+	}
+
+	cmd, err := cliargs.ParseWith([]string{"app", "-vvav", "--verbose"}, optCfgs)
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("verbose"))
+	assert.Equal(t, cmd.OptArgs("verbose"), []string{"4"})
+	assert.True(t, cmd.HasOpt("a"))
+
+	cmd, err = cliargs.ParseWith([]string{"app", "-vv", "-q"}, optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("verbose"), "1")
+	assert.False(t, cmd.HasOpt("quiet"))
+
+	cmd, err = cliargs.ParseWith([]string{"app", "-qq", "--quiet"}, optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("verbose"), "-3")
+
+	cmd, err = cliargs.ParseWith([]string{"app"}, optCfgs)
+	assert.Nil(t, err)
+	assert.False(t, cmd.HasOpt("verbose"))
+}
+
+func TestParseWith_counterOpt_turnedOff(t *testing.T) {
+	var onParsed []string
+	fn := func(a []string) error {
+		onParsed = a
+		return nil
+	}
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name:        "verbose",
+			Aliases:     []string{"v"},
+			IsCounter:   true,
+			IsNegatable: true,
+			OnParsed:    &fn,
+		},
+		cliargs.OptCfg{Name: "quiet", Decrements: "verbose"},
+	}
+
+	osArgs := []string{"app", "-vv", "--no-verbose", "-v"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("verbose"), "1")
+	assert.Equal(t, onParsed, []string{"1"})
+
+	osArgs = []string{"app", "-vv", "--verbose=off", "--quiet=no"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs, cliargs.WithBoolOptArgs())
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("verbose"), "0")
+	assert.Equal(t, onParsed, []string{"0"})
+}
+
+func TestParseWith_counterOpt_configError(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", HasArg: true, IsCounter: true},
+	}
+	_, err := cliargs.ParseWith([]string{"app"}, optCfgs)
+	assert.Equal(t, err.Error(), "ConfigIsCounterButHasArg{Option:verbose}")
+	switch e := err.(type) {
+	case cliargs.ConfigIsCounterButHasArg:
+		assert.Equal(t, e.Option, "verbose")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	optCfgs = []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose"},
+		cliargs.OptCfg{Name: "quiet", Decrements: "verbose"},
+	}
+	_, err = cliargs.ParseWith([]string{"app"}, optCfgs)
+	assert.Equal(t, err.Error(),
+		"ConfigDecrementsNonCounter{Option:quiet,Decrements:verbose}")
+	switch e := err.(type) {
+	case cliargs.ConfigDecrementsNonCounter:
+		assert.Equal(t, e.Option, "quiet")
+		assert.Equal(t, e.Decrements, "verbose")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	optCfgs = []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "quiet", Decrements: "verbose"},
+	}
+	_, err = cliargs.ParseWith([]string{"app"}, optCfgs)
+	switch err.(type) {
+	case cliargs.ConfigDecrementsNonCounter:
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseWith_optionalArg(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name:          "color",
+			Aliases:       []string{"c"},
+			HasArg:        true,
+			IsArgOptional: true,
+			ImplicitArg:   "always",
+			Default:       []string{"never"},
+		},
+		cliargs.OptCfg{
+			Name:          "log",
+			HasArg:        true,
+			IsArray:       true,
+			IsArgOptional: true,
+		},
+	}
+
+	osArgs := []string{"app", "--color", "file.txt"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("color"), "always")
+	assert.Equal(t, cmd.Args(), []string{"file.txt"})
+
+	osArgs = []string{"app", "-c=auto", "file.txt"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("color"), "auto")
+	assert.Equal(t, cmd.Args(), []string{"file.txt"})
+
+	osArgs = []string{"app", "file.txt"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("color"), "never")
+	assert.False(t, cmd.HasOpt("log"))
+
+	osArgs = []string{"app", "--log", "--log=a.log", "x"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArgs("log"), []string{"", "a.log"})
+	assert.Equal(t, cmd.Args(), []string{"x"})
+
+	osArgs = []string{"app", "-c", "--color=auto"}
+	_, err = cliargs.ParseWith(osArgs, optCfgs)
+	switch e := err.(type) {
+	case cliargs.OptionIsNotArray:
+		assert.Equal(t, e.Option, "color")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseWith_optionalArg_configHasNoArg(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "color", IsArgOptional: true},
+	}
+
+	_, err := cliargs.ParseWith([]string{"app"}, optCfgs)
+	assert.Equal(t, err.Error(), "ConfigIsArgOptionalButHasNoArg{Option:color}")
+	switch e := err.(type) {
+	case cliargs.ConfigIsArgOptionalButHasNoArg:
+		assert.Equal(t, e.Option, "color")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseWith_arity(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name: "point", Aliases: []string{"p"}, HasArg: true, Arity: 2,
+		},
+		cliargs.OptCfg{
+			Name: "rename", HasArg: true, IsArray: true, Arity: 2,
+		},
+		cliargs.OptCfg{Name: "v"},
+	}
+
+	osArgs := []string{"app", "--point", "1", "2", "--rename", "a", "b", "-v",
+		"--rename=c", "d", "x"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArgs("point"), []string{"1", "2"})
+	assert.Equal(t, cmd.OptArgGroups("point"), [][]string{{"1", "2"}})
+	assert.Equal(t, cmd.OptArgs("rename"), []string{"a", "b", "c", "d"})
+	assert.Equal(t, cmd.OptArgGroups("rename"),
+		[][]string{{"a", "b"}, {"c", "d"}})
+	assert.True(t, cmd.HasOpt("v"))
+	assert.Equal(t, cmd.OptArgGroups("v"), [][]string{})
+	assert.Nil(t, cmd.OptArgGroups("none"))
+	assert.Equal(t, cmd.Args(), []string{"x"})
+
+	osArgs = []string{"app", "-vp", "-1", "--", "x"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArgGroups("point"), [][]string{{"-1", "--"}})
+	assert.Equal(t, cmd.Args(), []string{"x"})
+
+	osArgs = []string{"app", "--point", "1", "2", "-p", "3", "4"}
+	_, err = cliargs.ParseWith(osArgs, optCfgs)
+	switch e := err.(type) {
+	case cliargs.OptionIsNotArray:
+		assert.Equal(t, e.Option, "point")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseWith_arity_needsMoreArgs(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "point", HasArg: true, Arity: 3},
+	}
+
+	cmd, err := cliargs.ParseWith([]string{"app", "--point", "1", "2"}, optCfgs)
+	assert.Equal(t, err.Error(),
+		"OptionNeedsMoreArgs{Option:point,Arity:3,Given:2}")
+	switch e := err.(type) {
+	case cliargs.OptionNeedsMoreArgs:
+		assert.Equal(t, e.Option, "point")
+		assert.Equal(t, e.Arity, 3)
+		assert.Equal(t, e.Given, 2)
+	default:
+		assert.Fail(t, err.Error())
+	}
+	assert.Equal(t, cmd.Args(), []string{})
+
+	_, err = cliargs.ParseWith([]string{"app", "--point=1"}, optCfgs)
+	switch e := err.(type) {
+	case cliargs.OptionNeedsMoreArgs:
+		assert.Equal(t, e.Given, 1)
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	_, err = cliargs.ParseWith([]string{"app", "--point"}, optCfgs)
+	switch e := err.(type) {
+	case cliargs.OptionNeedsArg:
+		assert.Equal(t, e.Option, "point")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseWith_arity_configError(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "point", Arity: 2},
+		cliargs.OptCfg{Name: "point", HasArg: true, IsArgOptional: true, Arity: 2},
+		cliargs.OptCfg{Name: "point", HasArg: true, Arity: 2, Default: []string{"1"}},
+		cliargs.OptCfg{Name: "point", HasArg: true, Arity: -1},
+	}
+
+	for _, cfg := range cfgs {
+		_, err := cliargs.ParseWith([]string{"app"}, []cliargs.OptCfg{cfg})
+		switch e := err.(type) {
+		case cliargs.ConfigHasInvalidArity:
+			assert.Equal(t, e.Option, "point")
+			assert.Equal(t, e.Arity, cfg.Arity)
+		default:
+			assert.Fail(t, err.Error())
+		}
+	}
+
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name: "point", HasArg: true, Arity: 2, Default: []string{"0", "0"},
+		},
+	}
+	cmd, err := cliargs.ParseWith([]string{"app"}, optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArgGroups("point"), [][]string{{"0", "0"}})
+}
+
+func TestParseWith_negativeNumbers(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "x"},
+		cliargs.OptCfg{Name: "offset", Aliases: []string{"o"}, HasArg: true},
+	}
+
+	osArgs := []string{"calc", "-5", "3", "-x", "-3.14", "-1e3", "-.5",
+		"--offset", "-7"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithNegativeNumbers())
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.Args(), []string{"-5", "3", "-3.14", "-1e3", "-.5"})
+	assert.True(t, cmd.HasOpt("x"))
+	assert.Equal(t, cmd.OptArg("offset"), "-7")
+
+	osArgs = []string{"calc", "-o=-8", "-9"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs, cliargs.WithNegativeNumbers())
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.Args(), []string{"-9"})
+	assert.Equal(t, cmd.OptArg("offset"), "-8")
+
+	osArgs = []string{"calc", "-5"}
+	_, err = cliargs.ParseWith(osArgs, optCfgs)
+	switch e := err.(type) {
+	case cliargs.OptionHasInvalidChar:
+		assert.Equal(t, e.Option, "5")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	osArgs = []string{"calc", "-1x"}
+	_, err = cliargs.ParseWith(osArgs, optCfgs, cliargs.WithNegativeNumbers())
+	switch e := err.(type) {
+	case cliargs.OptionHasInvalidChar:
+		assert.Equal(t, e.Option, "1")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	osArgs = []string{"calc", "-inf"}
+	_, err = cliargs.ParseWith(osArgs, optCfgs, cliargs.WithNegativeNumbers())
+	switch e := err.(type) {
+	case cliargs.UnconfiguredOption:
+		assert.Equal(t, e.Option, "i")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseWith_negativeNumbers_withDigitOpt(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "fast", Aliases: []string{"1"}},
+	}
+
+	osArgs := []string{"gzip", "-5"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithNegativeNumbers())
+	assert.Equal(t, err.Error(), "OptionHasInvalidChar{Option:5}")
+	assert.Equal(t, cmd.Args(), []string{})
+
+	osArgs = []string{"gzip", "-1"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs, cliargs.WithNegativeNumbers())
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("fast"))
+	assert.Equal(t, cmd.Args(), []string{})
+}
+
+func TestParseWith_digitOpts(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "fast", Aliases: []string{"1"}},
+		cliargs.OptCfg{Name: "best", Aliases: []string{"9"}},
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+	}
+
+	osArgs := []string{"gzip", "-9v", "file"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs)
+	assert.Nil(t, err)
+	assert.False(t, cmd.HasOpt("fast"))
+	assert.True(t, cmd.HasOpt("best"))
+	assert.True(t, cmd.HasOpt("verbose"))
+	assert.Equal(t, cmd.Args(), []string{"file"})
+
+	osArgs = []string{"gzip", "-5"}
+	_, err = cliargs.ParseWith(osArgs, optCfgs)
+	assert.Equal(t, err.Error(), "OptionHasInvalidChar{Option:5}")
+
+	osArgs = []string{"gzip", "-19"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs)
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("fast"))
+	assert.True(t, cmd.HasOpt("best"))
+}
+
+func TestParseWith_numberOpt(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name:        "lines",
+			Aliases:     []string{"n"},
+			HasArg:      true,
+			IsNumberOpt: true,
+		},
+		cliargs.OptCfg{Name: "quiet", Aliases: []string{"q"}},
+	}
+
+	osArgs := []string{"head", "-20", "file"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("lines"), "20")
+	assert.Equal(t, cmd.Args(), []string{"file"})
+
+	osArgs = []string{"head", "-n", "5", "-q", "file"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("lines"), "5")
+	assert.True(t, cmd.HasOpt("quiet"))
+
+	osArgs = []string{"head", "-20q"}
+	_, err = cliargs.ParseWith(osArgs, optCfgs)
+	assert.Equal(t, err.Error(), "OptionHasInvalidChar{Option:2}")
+
+	osArgs = []string{"head", "-5", "file"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs, cliargs.WithNegativeNumbers())
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("lines"), "5")
+	assert.Equal(t, cmd.Args(), []string{"file"})
+}
+
+func TestParseWith_numberOptHasNoArg(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "lines", IsNumberOpt: true},
+	}
+
+	osArgs := []string{"head", "-20"}
+	_, err := cliargs.ParseWith(osArgs, optCfgs)
+	assert.Equal(t, err.Error(), "ConfigIsNumberOptButHasNoArg{Option:lines}")
+	switch e := err.(type) {
+	case cliargs.ConfigIsNumberOptButHasNoArg:
+		assert.Equal(t, e.Option, "lines")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseWith_stopAtFirstArg(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+		cliargs.OptCfg{Name: "context", HasArg: true},
+	}
+
+	osArgs := []string{"mytool", "-v", "--context", "dev", "exec", "--",
+		"kubectl", "-n", "foo", "get", "pods"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithStopAtFirstArg())
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("verbose"))
+	assert.Equal(t, cmd.OptArg("context"), "dev")
+	assert.Equal(t, cmd.Args(), []string{"exec", "--", "kubectl", "-n", "foo",
+		"get", "pods"})
+
+	osArgs = []string{"mytool", "-v", "--", "-n", "foo", "--", "-v"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs, cliargs.WithStopAtFirstArg())
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("verbose"))
+	assert.Equal(t, cmd.Args(), []string{"-n", "foo", "--", "-v"})
+
+	osArgs = []string{"mytool", "-", "-v"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs, cliargs.WithStopAtFirstArg())
+	assert.Nil(t, err)
+	assert.False(t, cmd.HasOpt("verbose"))
+	assert.Equal(t, cmd.Args(), []string{"-", "-v"})
+
+	osArgs = []string{"mytool", "exec", "-n", "foo"}
+	_, err = cliargs.ParseWith(osArgs, optCfgs)
+	assert.Equal(t, err.Error(), "UnconfiguredOption{Option:n}")
+}
+
+func TestParseWith_stopAtFirstArg_negativeNumber(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+	}
+
+	osArgs := []string{"calc", "-v", "-5", "-v"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs,
+		cliargs.WithNegativeNumbers(), cliargs.WithStopAtFirstArg())
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("verbose"))
+	assert.Equal(t, cmd.Args(), []string{"-5", "-v"})
+}
+
+func TestParseWith_requiredOpt(t *testing.T) {
+	onParsed := func(a []string) error {
+		assert.Fail(t, "OnParsed must not be called.")
+		return nil
+	}
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", OnParsed: &onParsed},
+		cliargs.OptCfg{
+			Name:       "output",
+			Aliases:    []string{"o", "out"},
+			HasArg:     true,
+			IsRequired: true,
+		},
+	}
+
+	osArgs := []string{"app", "--verbose", "file"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs)
+	assert.Equal(t, err.Error(),
+		"MissingRequiredOption{Option:output,Aliases:[o out]}")
+	switch e := err.(type) {
+	case cliargs.MissingRequiredOption:
+		assert.Equal(t, e.Option, "output")
+		assert.Equal(t, e.Aliases, []string{"o", "out"})
+	default:
+		assert.Fail(t, err.Error())
+	}
+	assert.Equal(t, cmd.Args(), []string{})
+
+	optCfgs[0].OnParsed = nil
+
+	osArgs = []string{"app", "-o", "x", "file"}
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("output"), "x")
+	assert.Equal(t, cmd.Args(), []string{"file"})
+}
+
+func TestParseWith_requiredOpt_withDefault(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name:       "output",
+			HasArg:     true,
+			Default:    []string{"a.out"},
+			IsRequired: true,
+		},
+	}
+
+	cmd, err := cliargs.ParseWith([]string{"app"}, optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("output"), "a.out")
+}
+
+func TestParseWith_requiredOpt_negated(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "color", IsNegatable: true, IsRequired: true},
+	}
+
+	cmd, err := cliargs.ParseWith([]string{"app", "--no-color"}, optCfgs)
+	assert.Nil(t, err)
+	assert.False(t, cmd.HasOpt("color"))
+
+	_, err = cliargs.ParseWith([]string{"app"}, optCfgs)
+	assert.Equal(t, err.Error(),
+		"MissingRequiredOption{Option:color,Aliases:[]}")
+}
+
+func TestParseWith_choices(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name:    "format",
+			Aliases: []string{"f"},
+			HasArg:  true,
+			Choices: []string{"json", "yaml", "table"},
+		},
+		cliargs.OptCfg{
+			Name:    "level",
+			HasArg:  true,
+			IsArray: true,
+			Choices: []string{"debug", "info"},
+		},
+	}
+
+	cmd, err := cliargs.ParseWith(
+		[]string{"app", "-f", "yaml", "--level=debug", "--level", "info"}, optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("format"), "yaml")
+	assert.Equal(t, cmd.OptArgs("level"), []string{"debug", "info"})
+
+	_, err = cliargs.ParseWith([]string{"app", "--format=xml"}, optCfgs)
+	assert.Equal(t, err.Error(),
+		"OptionArgIsNotChoice{Option:format,OptArg:xml,Choices:[json yaml table]}")
+	switch e := err.(type) {
+	case cliargs.OptionArgIsNotChoice:
+		assert.Equal(t, e.Option, "format")
+		assert.Equal(t, e.OptArg, "xml")
+		assert.Equal(t, e.Choices, []string{"json", "yaml", "table"})
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	_, err = cliargs.ParseWith(
+		[]string{"app", "--level", "info", "--level", "warn"}, optCfgs)
+	assert.Equal(t, err.Error(),
+		"OptionArgIsNotChoice{Option:level,OptArg:warn,Choices:[debug info]}")
+}
+
+func TestParseWith_choices_implicitArgAndDefault(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name:          "color",
+			HasArg:        true,
+			IsArgOptional: true,
+			ImplicitArg:   "always",
+			Default:       []string{"auto"},
+			Choices:       []string{"always", "never"},
+		},
+	}
+
+	cmd, err := cliargs.ParseWith([]string{"app"}, optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("color"), "auto")
+
+	cmd, err = cliargs.ParseWith([]string{"app", "--color"}, optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("color"), "always")
+
+	optCfgs[0].ImplicitArg = "yes"
+	_, err = cliargs.ParseWith([]string{"app", "--color"}, optCfgs)
+	assert.Equal(t, err.Error(),
+		"OptionArgIsNotChoice{Option:color,OptArg:yes,Choices:[always never]}")
+}
+
+func TestParseWith_choicesButHasNoArg(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "format", Choices: []string{"json"}},
+	}
+
+	_, err := cliargs.ParseWith([]string{"app"}, optCfgs)
+	assert.Equal(t, err.Error(), "ConfigHasChoicesButHasNoArg{Option:format}")
+	switch e := err.(type) {
+	case cliargs.ConfigHasChoicesButHasNoArg:
+		assert.Equal(t, e.Option, "format")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"unicode"
 )
@@ -43,22 +44,56 @@ var (
 // configurations.
 // And this provides methods to check if they are specified or to obtain them.
 type Cmd struct {
-	Name string
-	args []string
-	opts map[string][]string
+	Name    string
+	args    []string
+	opts    map[string][]string
+	subCmd  *Cmd
+	parent  *Cmd
+	globals map[string]bool
+	arities map[string]int
+}
+
+func (cmd Cmd) lookupOpt(name string) ([]string, bool) {
+	// A nil entry is an option which is turned off by "--no-" or a false option
+	// argument but is not applied yet.
+	arr, exists := cmd.opts[name]
+	if exists {
+		return arr, arr != nil
+	}
+	for p := cmd.parent; p != nil; p = p.parent {
+		if p.globals[name] {
+			arr = p.opts[name]
+			return arr, arr != nil
+		}
+	}
+	return nil, false
+}
+
+func (cmd Cmd) lookupArity(name string) int {
+	if _, exists := cmd.opts[name]; exists {
+		return cmd.arities[name]
+	}
+	for p := cmd.parent; p != nil; p = p.parent {
+		if p.globals[name] {
+			return p.arities[name]
+		}
+	}
+	return 0
 }
 
 // HasOpt is a method which checks if the option is specified in command line
 // arguments.
+// For a sub command, this method also checks global options of its ancestor
+// commands.
 func (cmd Cmd) HasOpt(name string) bool {
-	_, exists := cmd.opts[name]
+	_, exists := cmd.lookupOpt(name)
 	return exists
 }
 
 // OptArg is a method to get a option argument which is firstly specified
 // with opt in command line arguments.
 func (cmd Cmd) OptArg(name string) string {
-	arr := cmd.opts[name]
+	arr, _ := cmd.lookupOpt(name)
 	// If no entry, map returns a nil slice.
 	// If a value of a found entry is an empty slice.
 	// Both returned values are zero length in common.
@@ -72,7 +107,29 @@ func (cmd Cmd) OptArg(name string) string {
 // OptArgs is a method to get option arguments which are all specified with
 // name in command line arguments.
 func (cmd Cmd) OptArgs(name string) []string {
-	return cmd.opts[name]
+	arr, _ := cmd.lookupOpt(name)
+	return arr
+}
+
+// OptArgGroups is a method to get option arguments which are specified with
+// name in command line arguments, and are grouped per occurrence.
+// If the option configuration of the option has Arity, each group has option
+// arguments as many as Arity, otherwise each group has one option argument.
+// (See OptCfg#Arity.)
+func (cmd Cmd) OptArgGroups(name string) [][]string {
+	arr, _ := cmd.lookupOpt(name)
+	if arr == nil {
+		return nil
+	}
+	n := cmd.lookupArity(name)
+	if n < 1 {
+		n = 1
+	}
+	groups := make([][]string, 0, len(arr)/n)
+	for i := 0; i+n <= len(arr); i += n {
+		groups = append(groups, arr[i:i+n])
+	}
+	return groups
 }
 
 // Args is a method to get command arguments which are specified in command
@@ -81,6 +138,16 @@ func (cmd Cmd) Args() []string {
 	return cmd.args
 }
 
+// SubCmd is a method to get a sub command which is specified in command line
+// arguments and follows this command.
+// If no sub command is specified, the second returned value is false.
+func (cmd Cmd) SubCmd() (Cmd, bool) {
+	if cmd.subCmd == nil {
+		return Cmd{args: empty}, false
+	}
+	return *cmd.subCmd, true
+}
+
 // Parse is a function to parse command line arguments without configurations.
 // This function divides command line arguments to command arguments, which
 // are not associated with any options, and options, of which each has a name
@@ -96,13 +163,19 @@ func (cmd Cmd) Args() []string {
 //
 // A short format option starts with "-" and follows single character which is
 // an alphabet.
+// (A digit is not allowed by this function, but is allowed by ParseWith if it
+// is configured as an option name.)
 // Multiple short options can be combined into one argument.
 // (For example -a -b -c can be combined into -abc.)
 // Moreover, a short option can be followed by "=" and its option argument.
 // In case of combined short options, only the last short option can take an
 // option argument.
 // (For example, -abc=3 is equal to -a -b -c=3.)
-func Parse() (Cmd, error) {
+//
+// The behavior of parsing can be changed by ParseOpt(s) which are given as
+// variadic arguments, but ParseOpt(s) which work with option configurations,
+// like WithAttachedShortArgs, have no effect on this function.
+func Parse(parseOpts ...ParseOpt) (Cmd, error) {
 	var args = make([]string, 0)
 	var opts = make(map[string][]string)
 
@@ -129,7 +202,18 @@ func Parse() (Cmd, error) {
 		osArgs1 = os.Args[1:]
 	}
 
-	err := parseArgs(osArgs1, collectArgs, collectOpts, _false)
+	pc := newParseCfg(parseOpts)
+
+	if pc.responseFiles {
+		var err error
+		osArgs1, err = expandResponseFiles(osArgs1)
+		if err != nil {
+			return Cmd{args: empty}, err
+		}
+	}
+
+	_, err := parseArgs(
+		osArgs1, collectArgs, collectOpts, _noArgs, nil, pc)
 	if err != nil {
 		return Cmd{args: empty}, err
 	}
@@ -137,31 +221,80 @@ func Parse() (Cmd, error) {
 	return Cmd{Name: cmdName, args: args, opts: opts}, err
 }
 
-func _false(_ string) bool {
-	return false
+// isNegativeNumber is a function which checks whether a command line argument
+// is a negative integer or floating point number, like -5, -3.14, or -1e3.
+func isNegativeNumber(arg string) bool {
+	if len(arg) < 2 || arg[0] != '-' {
+		return false
+	}
+	if (arg[1] < '0' || arg[1] > '9') && arg[1] != '.' {
+		return false
+	}
+	_, err := strconv.ParseFloat(arg, 64)
+	if e, ok := err.(*strconv.NumError); ok && e.Err == strconv.ErrRange {
+		return true
+	}
+	return err == nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+func _noArgs(_ string) int {
+	return 0
 }
 
 func parseArgs(
 	osArgs []string,
 	collectArgs func(...string) error,
 	collectOpts func(string, ...string) error,
-	takeArgs func(string) bool,
-) error {
+	takeArgs func(string) int,
+	resolveLongOpt func(string) (string, error),
+	pc parseCfg,
+) (int, error) {
 
 	isNonOpt := false
 	prevOptTakingArgs := ""
+	var prevOptArgs []string
+	numOfPrevOptArgs := 0
+
+	// takeFollowingArgs is a function which makes the option take the
+	// following command line arguments until the number of its option
+	// arguments reaches n, and returns false if the option has already taken
+	// enough option arguments.
+	var takeFollowingArgs = func(name string, a []string, n int) bool {
+		if len(a) >= n {
+			return false
+		}
+		prevOptTakingArgs = name
+		prevOptArgs = a
+		numOfPrevOptArgs = n
+		return true
+	}
 
 	for iArg, arg := range osArgs {
 		if isNonOpt {
+			// The command line arguments after "--" are not taken as a sub command
+			// name even if the parsing stops at the first command argument.
 			err := collectArgs(arg)
 			if err != nil {
-				return err
+				return -1, err
 			}
 
 		} else if len(prevOptTakingArgs) > 0 {
-			err := collectOpts(prevOptTakingArgs, arg)
+			prevOptArgs = append(prevOptArgs, arg)
+			if len(prevOptArgs) < numOfPrevOptArgs {
+				continue
+			}
+			err := collectOpts(prevOptTakingArgs, prevOptArgs...)
 			if err != nil {
-				return err
+				return -1, err
 			}
 			prevOptTakingArgs = ""
 
@@ -176,85 +309,160 @@ func parseArgs(
 			for _, r := range arg {
 				if i > 0 {
 					if r == '=' {
-						err := collectOpts(arg[0:i], arg[i+1:])
+						name := arg[0:i]
+						if resolveLongOpt != nil {
+							var err error
+							name, err = resolveLongOpt(name)
+							if err != nil {
+								return -1, err
+							}
+						}
+						a := []string{arg[i+1:]}
+						if takeFollowingArgs(name, a, takeArgs(name)) {
+							break
+						}
+						err := collectOpts(name, a...)
 						if err != nil {
-							return err
+							return -1, err
 						}
 						break
 					}
 					if !unicode.Is(rangeOfAlNumMarks, r) {
-						return OptionHasInvalidChar{Option: arg}
+						return -1, OptionHasInvalidChar{Option: arg}
 					}
 				} else {
 					if !unicode.Is(rangeOfAlphabets, r) {
-						return OptionHasInvalidChar{Option: arg}
+						return -1, OptionHasInvalidChar{Option: arg}
 					}
 				}
 				i++
 			}
 
 			if i == len(arg) {
-				if takeArgs(arg) && iArg < len(osArgs)-1 {
-					prevOptTakingArgs = arg
+				if resolveLongOpt != nil {
+					var err error
+					arg, err = resolveLongOpt(arg)
+					if err != nil {
+						return -1, err
+					}
+				}
+				if takeFollowingArgs(arg, nil, takeArgs(arg)) {
 					continue
 				}
 				err := collectOpts(arg)
 				if err != nil {
-					return err
+					return -1, err
 				}
 			}
 
+		} else if pc.negativeNumbers && isNegativeNumber(arg) {
+			if pc.untilFirstArg {
+				return iArg, nil
+			}
+			if pc.stopAtFirstArg {
+				return -1, collectArgs(osArgs[iArg:]...)
+			}
+			err := collectArgs(arg)
+			if err != nil {
+				return -1, err
+			}
+
 		} else if strings.HasPrefix(arg, "-") {
 			if len(arg) == 1 {
+				if pc.untilFirstArg {
+					return iArg, nil
+				}
+				if pc.stopAtFirstArg {
+					return -1, collectArgs(osArgs[iArg:]...)
+				}
 				err := collectArgs(arg)
 				if err != nil {
-					return err
+					return -1, err
 				}
 				continue
 			}
 
 			arg := arg[1:]
+
+			if len(pc.numberOpt) > 0 && isDigits(arg) &&
+				!(len(arg) == 1 && strings.Contains(pc.digitOpts, arg)) {
+				err := collectOpts(pc.numberOpt, arg)
+				if err != nil {
+					return -1, err
+				}
+				continue
+			}
+
 			var name string
 			i := 0
 			for _, r := range arg {
 				if i > 0 {
 					if r == '=' {
-						err := collectOpts(name, arg[i+1:])
+						a := []string{arg[i+1:]}
+						if takeFollowingArgs(name, a, takeArgs(name)) {
+							break
+						}
+						err := collectOpts(name, a...)
 						if err != nil {
-							return err
+							return -1, err
+						}
+						break
+					}
+					if n := takeArgs(name); pc.attachedShortArgs && n > 0 {
+						a := []string{arg[i:]}
+						if takeFollowingArgs(name, a, n) {
+							break
+						}
+						err := collectOpts(name, a...)
+						if err != nil {
+							return -1, err
 						}
 						break
 					}
 					err := collectOpts(name)
 					if err != nil {
-						return err
+						return -1, err
 					}
 				}
 				name = string(r)
-				if !unicode.Is(rangeOfAlphabets, r) {
-					return OptionHasInvalidChar{Option: name}
+				if !unicode.Is(rangeOfAlphabets, r) &&
+					!strings.ContainsRune(pc.digitOpts, r) {
+					return -1, OptionHasInvalidChar{Option: name}
 				}
 				i++
 			}
 
 			if i == len(arg) {
-				if takeArgs(name) && iArg < len(osArgs)-1 {
-					prevOptTakingArgs = name
-				} else {
+				if !takeFollowingArgs(name, nil, takeArgs(name)) {
 					err := collectOpts(name)
 					if err != nil {
-						return err
+						return -1, err
 					}
 				}
 			}
 
 		} else {
+			if pc.untilFirstArg {
+				return iArg, nil
+			}
+			if pc.stopAtFirstArg {
+				return -1, collectArgs(osArgs[iArg:]...)
+			}
 			err := collectArgs(arg)
 			if err != nil {
-				return err
+				return -1, err
 			}
 		}
 	}
 
-	return nil
+	// The last option which lacks option arguments is collected with the
+	// taken option arguments, and the lack is checked by collectOpts.
+	if len(prevOptTakingArgs) > 0 {
+		err := collectOpts(prevOptTakingArgs, prevOptArgs...)
+		if err != nil {
+			return -1, err
+		}
+	}
+
+	return -1, nil
 }
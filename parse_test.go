@@ -439,6 +439,21 @@ func TestParse_IllegalCharInShortOpt(t *testing.T) {
 	assert.Equal(t, cmd.OptArgs("silent"), []string(nil))
 }
 
+func TestParse_illegalShortOptIfNumber(t *testing.T) {
+	defer resetOsArgs()
+
+	os.Args = make([]string, 2)
+	os.Args[0] = "app"
+	os.Args[1] = "-9"
+
+	cmd, err := cliargs.Parse()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "OptionHasInvalidChar{Option:9}")
+	assert.Equal(t, cmd.Args(), []string{})
+	assert.False(t, cmd.HasOpt("9"))
+}
+
 func TestParse_useEndOptMark(t *testing.T) {
 	defer resetOsArgs()
 
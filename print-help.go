@@ -21,6 +21,8 @@ type Help struct {
 type block struct {
 	indent, marginLeft, marginRight int
 	texts                           []string
+	lbo                             func(rune) lboType
+	hasUsageSpaces                  bool
 }
 
 // NewHelp is a function to create a Help instance.
@@ -80,11 +82,12 @@ func (iter *HelpIter) Next() (string, IterStatus) {
 }
 
 type blockIter struct {
-	texts    []string
-	index    int
-	indent   int
-	margin   string
-	lineIter lineIter
+	texts          []string
+	index          int
+	indent         int
+	margin         string
+	lineIter       lineIter
+	hasUsageSpaces bool
 }
 
 func newBlockIter(b block, lineWidth int) blockIter {
@@ -95,12 +98,17 @@ func newBlockIter(b block, lineWidth int) blockIter {
 	if printWidth <= b.indent {
 		return blockIter{}
 	}
-	return blockIter{
-		texts:    b.texts,
-		indent:   b.indent,
-		margin:   strings.Repeat(" ", b.marginLeft),
-		lineIter: newLineIter(b.texts[0], printWidth),
+	iter := blockIter{
+		texts:          b.texts,
+		indent:         b.indent,
+		margin:         strings.Repeat(" ", b.marginLeft),
+		lineIter:       newLineIter(b.texts[0], printWidth),
+		hasUsageSpaces: b.hasUsageSpaces,
 	}
+	if b.lbo != nil {
+		iter.lineIter.lbo = b.lbo
+	}
+	return iter
 }
 
 func (iter *blockIter) next() (string, IterStatus) {
@@ -110,6 +118,9 @@ func (iter *blockIter) next() (string, IterStatus) {
 
 	line, status := iter.lineIter.Next()
 	if len(line) > 0 {
+		if iter.hasUsageSpaces {
+			line = strings.ReplaceAll(line, string(usageSpace), " ")
+		}
 		line = iter.margin + line
 	}
 	if status == ITER_NO_MORE {
@@ -168,10 +179,177 @@ func (help *Help) AddTexts(texts []string, wrapOpts ...int) {
 	help.blocks = append(help.blocks, b)
 }
 
+// AddUsage is a method which adds a usage synopsis which is generated from a
+// command name, OptCfg(s), and texts of command arguments to this Help
+// instance, like:
+//
+//	Usage: app [-v] [--baz <num>...] <file>...
+//
+// Each option is enclosed in brackets unless it is required, and followed by
+// its ArgHelp (or its Choices like {json|yaml}, or <value> if both are empty)
+// if it takes an option argument, and by "..." if it can be specified multiple
+// times (IsArray is true).
+// The texts of command arguments are output as they are after the options,
+// and can be made from ArgCfg(s) with MakeUsageArgs function.
+//
+// A long usage synopsis is wrapped only between the items, and the following
+// lines are indented to the position after the command name.
+// And this method can optionally set indent, left margin, and right margin as
+// variadic arguments, too.
+func (help *Help) AddUsage(
+	cmdName string, optCfgs []OptCfg, args []string, wrapOpts ...int,
+) {
+	b := block{
+		marginLeft:     help.marginLeft,
+		marginRight:    help.marginRight,
+		lbo:            spaceLineBreakOppotunity,
+		hasUsageSpaces: true,
+	}
+
+	head := "Usage: " + cmdName
+	b.indent = textWidth(head) + 1
+
+	if len(wrapOpts) > 0 {
+		b.indent = wrapOpts[0]
+	}
+	if len(wrapOpts) > 1 {
+		b.marginLeft += wrapOpts[1]
+	}
+	if len(wrapOpts) > 2 {
+		b.marginRight += wrapOpts[2]
+	}
+
+	items := make([]string, 0, len(optCfgs)+len(args)+1)
+	items = append(items, head)
+	for _, cfg := range optCfgs {
+		if cfg.Name == anyOption {
+			continue
+		}
+		items = append(items, makeUsageOpt(cfg))
+	}
+	items = append(items, args...)
+
+	for i, item := range items {
+		items[i] = strings.ReplaceAll(item, " ", string(usageSpace))
+	}
+
+	b.texts = []string{strings.Join(items, " ")}
+	help.blocks = append(help.blocks, b)
+}
+
+func makeUsageOpt(cfg OptCfg) string {
+	item := optTitleWord(cfg.Name, cfg.IsNegatable)
+	if cfg.HasArg {
+		argHelp := optArgHelp(cfg)
+		if len(argHelp) == 0 {
+			argHelp = "<value>"
+			for i := 1; i < cfg.Arity; i++ {
+				argHelp += " <value>"
+			}
+		}
+		if cfg.IsArgOptional {
+			item += "[=" + argHelp + "]"
+		} else {
+			item += " " + argHelp
+		}
+	}
+	if cfg.IsArray || cfg.IsCounter {
+		item += "..."
+	}
+	if cfg.IsRequired {
+		return item
+	}
+	return "[" + item + "]"
+}
+
 // AddOpts is a method which adds OptCfg(s) to this Help instance.
 // And this method can optionally set indent, left margin, and right margin as
 // variadic arguments, too.
+// The options of which OptCfg#IsGlobal is true are not added by this method
+// but by AddGlobalOpts method.
 func (help *Help) AddOpts(optCfgs []OptCfg, wrapOpts ...int) {
+	help.addOpts(selectOptCfgs(optCfgs, false), wrapOpts)
+}
+
+// AddGlobalOpts is a method which adds OptCfg(s) of which IsGlobal is true to
+// this Help instance.
+// This method is used to output global options in a section separated from
+// the section of other options.
+// And this method can optionally set indent, left margin, and right margin as
+// variadic arguments, too.
+func (help *Help) AddGlobalOpts(optCfgs []OptCfg, wrapOpts ...int) {
+	help.addOpts(selectOptCfgs(optCfgs, true), wrapOpts)
+}
+
+func selectOptCfgs(optCfgs []OptCfg, isGlobal bool) []OptCfg {
+	selected := make([]OptCfg, 0, len(optCfgs))
+	for _, cfg := range optCfgs {
+		if cfg.IsGlobal == isGlobal {
+			selected = append(selected, cfg)
+		}
+	}
+	return selected
+}
+
+func (help *Help) addOpts(optCfgs []OptCfg, wrapOpts []int) {
+	titles := make([]string, 0, len(optCfgs))
+	descs := make([]string, 0, len(optCfgs))
+	for _, cfg := range optCfgs {
+		if cfg.Name == anyOption {
+			continue
+		}
+		titles = append(titles, makeOptTitle(cfg))
+		descs = append(descs, makeOptDesc(cfg))
+	}
+	help.addTitledTexts(titles, descs, wrapOpts)
+}
+
+// AddArgs is a method which adds ArgCfg(s) to this Help instance, like:
+//
+//	<src>        The source file.
+//	[<dest>...]  The destination directories.
+//
+// An optional command argument is enclosed in brackets, and a variadic
+// command argument is followed by "...".
+// And this method can optionally set indent, left margin, and right margin as
+// variadic arguments, too.
+func (help *Help) AddArgs(argCfgs []ArgCfg, wrapOpts ...int) {
+	titles := make([]string, len(argCfgs))
+	descs := make([]string, len(argCfgs))
+	for i, cfg := range argCfgs {
+		titles[i] = makeArgTitle(cfg)
+		descs[i] = cfg.Desc
+	}
+	help.addTitledTexts(titles, descs, wrapOpts)
+}
+
+// AddConstraints is a method which adds the descriptions of OptConstraint(s)
+// to this Help instance, like:
+//
+//	--json | --table (mutually exclusive)
+//	--key requires --cert
+//	--all implies --recursive
+//
+// And this method can optionally set indent, left margin, and right margin as
+// variadic arguments, too.
+func (help *Help) AddConstraints(
+	constraints []OptConstraint, wrapOpts ...int,
+) {
+	texts := make([]string, 0, len(constraints))
+	for _, c := range constraints {
+		text := makeConstraintText(c)
+		if len(text) > 0 {
+			texts = append(texts, text)
+		}
+	}
+	help.AddTexts(texts, wrapOpts...)
+}
+
+// addTitledTexts is a method which adds a block in which each description
+// follows its title.
+// If the indent is not given, the descriptions are aligned after the longest
+// title.
+func (help *Help) addTitledTexts(titles, descs []string, wrapOpts []int) {
 	b := block{
 		marginLeft:  help.marginLeft,
 		marginRight: help.marginRight,
@@ -186,53 +364,34 @@ func (help *Help) AddOpts(optCfgs []OptCfg, wrapOpts ...int) {
 		b.marginRight += wrapOpts[2]
 	}
 
-	texts := make([]string, len(optCfgs))
+	texts := make([]string, len(titles))
 
 	if b.indent > 0 {
-		i := 0
-		for _, cfg := range optCfgs {
-			if cfg.Name == anyOption {
-				continue
-			}
-			texts[i] = makeOptTitle(cfg)
-			width := textWidth(texts[i])
+		for i, title := range titles {
+			width := textWidth(title)
 			if width+2 > b.indent {
-				texts[i] += "\n" + strings.Repeat(" ", b.indent) + cfg.Desc
+				texts[i] = title + "\n" + strings.Repeat(" ", b.indent) + descs[i]
 			} else {
-				texts[i] += strings.Repeat(" ", b.indent-width) + cfg.Desc
+				texts[i] = title + strings.Repeat(" ", b.indent-width) + descs[i]
 			}
-			i++
 		}
-		texts = texts[0:i]
 
 	} else {
-		widths := make([]int, len(texts))
+		widths := make([]int, len(titles))
 		indent := 0
 
-		i := 0
-		for _, cfg := range optCfgs {
-			if cfg.Name == anyOption {
-				continue
-			}
-			texts[i] = makeOptTitle(cfg)
-			widths[i] = textWidth(texts[i])
+		for i, title := range titles {
+			widths[i] = textWidth(title)
 			if indent < widths[i] {
 				indent = widths[i]
 			}
-			i++
 		}
-		texts = texts[0:i]
 		indent += 2
 
 		b.indent = indent
 
-		i = 0
-		for _, cfg := range optCfgs {
-			if cfg.Name == anyOption {
-				continue
-			}
-			texts[i] += strings.Repeat(" ", indent-widths[i]) + cfg.Desc
-			i++
+		for i, title := range titles {
+			texts[i] = title + strings.Repeat(" ", indent-widths[i]) + descs[i]
 		}
 	}
 
@@ -240,33 +399,60 @@ func (help *Help) AddOpts(optCfgs []OptCfg, wrapOpts ...int) {
 	help.blocks = append(help.blocks, b)
 }
 
-func makeOptTitle(cfg OptCfg) string {
-	title := cfg.Name
-	switch len(title) {
-	case 0:
-	case 1:
-		title = "-" + title
-	default:
-		title = "--" + title
+const requiredMark = "(required)"
+
+// makeOptDesc is a function which returns the description of an option for a
+// display, which is marked with "(required)" if the option is required.
+func makeOptDesc(cfg OptCfg) string {
+	if !cfg.IsRequired {
+		return cfg.Desc
 	}
+	if len(cfg.Desc) == 0 {
+		return requiredMark
+	}
+	return cfg.Desc + " " + requiredMark
+}
+
+func makeOptTitle(cfg OptCfg) string {
+	title := optTitleWord(cfg.Name, cfg.IsNegatable)
 
 	for _, alias := range cfg.Aliases {
-		switch len(alias) {
-		case 0:
-		case 1:
-			title += ", -" + alias
-		default:
-			title += ", --" + alias
+		if len(alias) > 0 {
+			title += ", " + optTitleWord(alias, cfg.IsNegatable)
 		}
 	}
 
-	if cfg.HasArg && len(cfg.ArgHelp) > 0 {
-		title += " " + cfg.ArgHelp
+	if argHelp := optArgHelp(cfg); cfg.HasArg && len(argHelp) > 0 {
+		if cfg.IsArgOptional {
+			title += "[=" + argHelp + "]"
+		} else {
+			title += " " + argHelp
+		}
 	}
 
 	return title
 }
 
+// optArgHelp is a function which returns the display of the option argument
+// of an option, which is ArgHelp, or is made from Choices like {json|yaml} if
+// ArgHelp is empty.
+func optArgHelp(cfg OptCfg) string {
+	if len(cfg.ArgHelp) == 0 && len(cfg.Choices) > 0 {
+		return "{" + strings.Join(cfg.Choices, "|") + "}"
+	}
+	return cfg.ArgHelp
+}
+
+// optTitleWord is a function which returns an option name with its heading
+// hyphens for a display.
+// A long name of a negatable option is displayed like --[no-]name.
+func optTitleWord(name string, isNegatable bool) string {
+	if isNegatable && len(name) > 1 {
+		return "--" + negMarker + name
+	}
+	return optWord(name)
+}
+
 func textWidth(text string) int {
 	w := 0
 	for _, r := range text {
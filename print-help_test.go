@@ -622,6 +622,126 @@ func TestAddOpts_hasAnyOption_withIndent(t *testing.T) {
 	assert.Equal(t, status, cliargs.ITER_NO_MORE)
 }
 
+func TestAddOpts_separateGlobalOpts(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name:     "verbose",
+			Aliases:  []string{"v"},
+			Desc:     "a12345678",
+			IsGlobal: true,
+		},
+		cliargs.OptCfg{
+			Name: "foo-bar",
+			Desc: "b12345678",
+		},
+	}
+
+	help := cliargs.NewHelp()
+	help.AddText("OPTIONS:")
+	help.AddOpts(optCfgs, 0, 2)
+	help.AddText("GLOBAL OPTIONS:")
+	help.AddGlobalOpts(optCfgs, 0, 2)
+	iter := help.Iter()
+
+	line, status := iter.Next()
+	assert.Equal(t, line, "OPTIONS:")
+	assert.Equal(t, status, cliargs.ITER_HAS_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "  --foo-bar  b12345678")
+	assert.Equal(t, status, cliargs.ITER_HAS_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "GLOBAL OPTIONS:")
+	assert.Equal(t, status, cliargs.ITER_HAS_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "  --verbose, -v  a12345678")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+}
+
+func TestAddUsage_oneLine(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "v"},
+		cliargs.OptCfg{
+			Name:    "baz",
+			HasArg:  true,
+			IsArray: true,
+			ArgHelp: "<num>",
+		},
+		cliargs.OptCfg{Name: "qux", HasArg: true},
+		cliargs.OptCfg{Name: "*"},
+	}
+
+	help := cliargs.NewHelp()
+	help.AddUsage("app", optCfgs, []string{"<file>..."})
+	iter := help.Iter()
+
+	line, status := iter.Next()
+	assert.Equal(t, line,
+		"Usage: app [-v] [--baz <num>...] [--qux <value>] <file>...")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+}
+
+func TestAddUsage_withWrapping(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose"},
+		cliargs.OptCfg{
+			Name:    "foo-bar-baz-qux",
+			HasArg:  true,
+			ArgHelp: "<some text>",
+		},
+		cliargs.OptCfg{
+			Name:    "baz",
+			HasArg:  true,
+			IsArray: true,
+			ArgHelp: "<num>",
+		},
+		cliargs.OptCfg{Name: "config-file-name", HasArg: true},
+		cliargs.OptCfg{Name: "q"},
+	}
+
+	help := cliargs.NewHelp()
+	help.AddUsage("my-app", optCfgs, []string{"<file>..."})
+	iter := help.Iter()
+
+	line, status := iter.Next()
+	assert.Equal(t, line, "Usage: my-app [--verbose] [--foo-bar-baz-qux <some text>] [--baz <num>...] ")
+	assert.Equal(t, status, cliargs.ITER_HAS_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "              [--config-file-name <value>] [-q] <file>...")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+}
+
+func TestAddUsage_withWrapOpts(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo-bar-baz", HasArg: true, ArgHelp: "<text>"},
+		cliargs.OptCfg{Name: "qux-quux-corge", HasArg: true, ArgHelp: "<text>"},
+		cliargs.OptCfg{Name: "grault-garply", HasArg: true, ArgHelp: "<text>"},
+	}
+
+	help := cliargs.NewHelp()
+	help.AddUsage("app", optCfgs, nil, 4, 2, 10)
+	iter := help.Iter()
+
+	line, status := iter.Next()
+	assert.Equal(t, line, "  Usage: app [--foo-bar-baz <text>] [--qux-quux-corge <text>] ")
+	assert.Equal(t, status, cliargs.ITER_HAS_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "      [--grault-garply <text>]")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+}
+
 func TestNewHelp_ifLineWidthLessThanSumOfMargins(t *testing.T) {
 	help := cliargs.NewHelp(71, 10)
 	iter := help.Iter()
@@ -796,3 +916,267 @@ For all options use the manual or "--help all".`)
 
 	help.Print()
 }
+
+func TestAddOpts_negatableOpt(t *testing.T) {
+	help := cliargs.NewHelp()
+	help.AddOpts([]cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name:        "color",
+			Aliases:     []string{"c", "colour"},
+			IsNegatable: true,
+			Desc:        "Colorize output.",
+		},
+	})
+	iter := help.Iter()
+
+	line, status := iter.Next()
+	assert.Equal(t, line, "--[no-]color, -c, --[no-]colour  Colorize output.")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+}
+
+func TestAddUsage_negatableOpt(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "color", IsNegatable: true},
+		cliargs.OptCfg{Name: "q", IsNegatable: true},
+	}
+
+	help := cliargs.NewHelp()
+	help.AddUsage("app", optCfgs, nil)
+	iter := help.Iter()
+
+	line, status := iter.Next()
+	assert.Equal(t, line, "Usage: app [--[no-]color] [-q]")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+}
+
+func TestAddUsage_counterOpt(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "v", IsCounter: true},
+		cliargs.OptCfg{Name: "q", Decrements: "v"},
+	}
+
+	help := cliargs.NewHelp()
+	help.AddUsage("app", optCfgs, nil)
+	iter := help.Iter()
+
+	line, status := iter.Next()
+	assert.Equal(t, line, "Usage: app [-v...] [-q]")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+}
+
+func TestAddOpts_optionalArg(t *testing.T) {
+	help := cliargs.NewHelp()
+	help.AddOpts([]cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name:          "color",
+			Aliases:       []string{"c"},
+			HasArg:        true,
+			IsArgOptional: true,
+			ArgHelp:       "<when>",
+			Desc:          "Colorize output.",
+		},
+	})
+	iter := help.Iter()
+
+	line, status := iter.Next()
+	assert.Equal(t, line, "--color, -c[=<when>]  Colorize output.")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+}
+
+func TestAddUsage_optionalArg(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name: "color", HasArg: true, IsArgOptional: true, ArgHelp: "<when>",
+		},
+		cliargs.OptCfg{Name: "log", HasArg: true, IsArgOptional: true},
+	}
+
+	help := cliargs.NewHelp()
+	help.AddUsage("app", optCfgs, nil)
+	iter := help.Iter()
+
+	line, status := iter.Next()
+	assert.Equal(t, line, "Usage: app [--color[=<when>]] [--log[=<value>]]")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+}
+
+func TestAddUsage_arity(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "point", HasArg: true, Arity: 2},
+		cliargs.OptCfg{
+			Name: "rename", HasArg: true, IsArray: true, Arity: 2,
+			ArgHelp: "<old> <new>",
+		},
+	}
+
+	help := cliargs.NewHelp()
+	help.AddUsage("app", optCfgs, nil)
+	iter := help.Iter()
+
+	line, status := iter.Next()
+	assert.Equal(t, line,
+		"Usage: app [--point <value> <value>] [--rename <old> <new>...]")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+}
+
+func TestAddArgs(t *testing.T) {
+	argCfgs := []cliargs.ArgCfg{
+		cliargs.ArgCfg{Name: "src", Desc: "The source file."},
+		cliargs.ArgCfg{
+			Name:       "dest",
+			IsOptional: true,
+			IsVariadic: true,
+			Desc:       "The destination directories.",
+		},
+	}
+
+	help := cliargs.NewHelp()
+	help.AddText("ARGUMENTS:")
+	help.AddArgs(argCfgs, 0, 2)
+	help.AddUsage("app", nil, cliargs.MakeUsageArgs(argCfgs))
+	iter := help.Iter()
+
+	line, status := iter.Next()
+	assert.Equal(t, line, "ARGUMENTS:")
+	assert.Equal(t, status, cliargs.ITER_HAS_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "  <src>        The source file.")
+	assert.Equal(t, status, cliargs.ITER_HAS_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "  [<dest>...]  The destination directories.")
+	assert.Equal(t, status, cliargs.ITER_HAS_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "Usage: app <src> [<dest>...]")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+}
+
+func TestAddArgs_withIndent(t *testing.T) {
+	argCfgs := []cliargs.ArgCfg{
+		cliargs.ArgCfg{Name: "file", IsVariadic: true, Desc: "The input files."},
+	}
+
+	help := cliargs.NewHelp()
+	help.AddArgs(argCfgs, 5)
+	iter := help.Iter()
+
+	line, status := iter.Next()
+	assert.Equal(t, line, "<file>...")
+	assert.Equal(t, status, cliargs.ITER_HAS_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "     The input files.")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+}
+
+func TestAddOpts_requiredOpt(t *testing.T) {
+	help := cliargs.NewHelp()
+	help.AddOpts([]cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name:       "output",
+			Aliases:    []string{"o"},
+			HasArg:     true,
+			ArgHelp:    "<file>",
+			IsRequired: true,
+			Desc:       "The output file.",
+		},
+		cliargs.OptCfg{Name: "name", HasArg: true, IsRequired: true},
+	})
+	iter := help.Iter()
+
+	line, status := iter.Next()
+	assert.Equal(t, line, "--output, -o <file>  The output file. (required)")
+	assert.Equal(t, status, cliargs.ITER_HAS_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "--name               (required)")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+}
+
+func TestAddUsage_requiredOpt(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+		cliargs.OptCfg{
+			Name: "output", HasArg: true, ArgHelp: "<file>", IsRequired: true,
+		},
+	}
+
+	help := cliargs.NewHelp()
+	help.AddUsage("app", optCfgs, nil)
+	iter := help.Iter()
+
+	line, status := iter.Next()
+	assert.Equal(t, line, "Usage: app [--verbose] --output <file>")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+}
+
+func TestAddOpts_choices(t *testing.T) {
+	help := cliargs.NewHelp()
+	help.AddOpts([]cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name:    "format",
+			Aliases: []string{"f"},
+			HasArg:  true,
+			Choices: []string{"json", "yaml"},
+			Desc:    "The output format.",
+		},
+		cliargs.OptCfg{
+			Name:    "level",
+			HasArg:  true,
+			ArgHelp: "<level>",
+			Choices: []string{"debug", "info"},
+			Desc:    "The log level.",
+		},
+	})
+	iter := help.Iter()
+
+	line, status := iter.Next()
+	assert.Equal(t, line, "--format, -f {json|yaml}  The output format.")
+	assert.Equal(t, status, cliargs.ITER_HAS_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "--level <level>           The log level.")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+}
+
+func TestAddUsage_choices(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name: "format", HasArg: true, Choices: []string{"json", "yaml"},
+		},
+	}
+
+	help := cliargs.NewHelp()
+	help.AddUsage("app", optCfgs, nil)
+	iter := help.Iter()
+
+	line, status := iter.Next()
+	assert.Equal(t, line, "Usage: app [--format {json|yaml}]")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+}
+
+func TestAddText_noBreakSpaceIsKept(t *testing.T) {
+	help := cliargs.NewHelp()
+	help.AddUsage("app", []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "output", HasArg: true, ArgHelp: "<file>"},
+	}, nil)
+	help.AddText("abc\u00a0def")
+	help.AddOpts([]cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo", Desc: "ghi\u00a0jkl"},
+	})
+	iter := help.Iter()
+
+	line, status := iter.Next()
+	assert.Equal(t, line, "Usage: app [--output <file>]")
+	assert.Equal(t, status, cliargs.ITER_HAS_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "abc\u00a0def")
+	assert.Equal(t, status, cliargs.ITER_HAS_MORE)
+
+	line, status = iter.Next()
+	assert.Equal(t, line, "--foo  ghi\u00a0jkl")
+	assert.Equal(t, status, cliargs.ITER_NO_MORE)
+}
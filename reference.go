@@ -0,0 +1,232 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargs
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// MakeMarkdownReference is a function which generates a reference document of
+// a command and its sub commands in Markdown from a command configuration.
+// If you have only an OptCfg array, wrap it with a CmdCfg like
+// CmdCfg{Name: "app", OptCfgs: optCfgs}.
+//
+// The document has a section for each command, which consists of the Desc of
+// the command, the table of options, the table of global options, and the list
+// of sub commands.
+// The tables of options show the names, aliases, ArgHelp, Default, and Desc of
+// the options in the same way as AddOpts and AddGlobalOpts methods of Help.
+// The global options of a sub command include global options inherited from
+// its ancestor commands.
+func MakeMarkdownReference(cmdCfg CmdCfg) string {
+	var sb strings.Builder
+
+	for i, node := range collectCompNodes(cmdCfg) {
+		if i == 0 {
+			fmt.Fprintf(&sb, "# %s\n", mdEscape(node.path))
+		} else {
+			fmt.Fprintf(&sb, "\n## %s\n", mdEscape(node.path))
+		}
+
+		for _, para := range splitParagraphs(node.cmdCfg.Desc) {
+			sb.WriteString("\n" + mdEscape(para) + "\n")
+		}
+
+		writeMdOptTable(&sb, "Options", selectOptCfgs(node.optCfgs, false))
+		writeMdOptTable(&sb, "Global options", selectOptCfgs(node.optCfgs, true))
+
+		if len(node.cmdCfg.SubCmds) > 0 {
+			sb.WriteString("\n### Sub commands\n\n")
+			for _, sub := range node.cmdCfg.SubCmds {
+				subPath := node.path + " " + sub.Name
+				fmt.Fprintf(&sb, "- [`%s`](#%s)", subPath, refAnchor(subPath))
+				desc := firstLine(sub.Desc)
+				if len(desc) > 0 {
+					sb.WriteString(" - " + mdEscape(desc))
+				}
+				sb.WriteString("\n")
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+func writeMdOptTable(sb *strings.Builder, title string, optCfgs []OptCfg) {
+	if len(optCfgs) == 0 {
+		return
+	}
+
+	fmt.Fprintf(sb, "\n### %s\n\n", title)
+	sb.WriteString("| Option | Aliases | Argument | Default | Description |\n")
+	sb.WriteString("| --- | --- | --- | --- | --- |\n")
+
+	for _, cfg := range optCfgs {
+		aliases := make([]string, len(cfg.Aliases))
+		for i, a := range cfg.Aliases {
+			aliases[i] = "`" + optTitleWord(a, cfg.IsNegatable) + "`"
+		}
+
+		var arg string
+		if cfg.HasArg && len(optArgHelp(cfg)) > 0 {
+			arg = "`" + refArgHelp(cfg) + "`"
+		}
+
+		defaults := make([]string, len(cfg.Default))
+		for i, d := range cfg.Default {
+			defaults[i] = "`" + d + "`"
+		}
+
+		lines := strings.Split(strings.TrimSpace(makeOptDesc(cfg)), "\n")
+		for i, line := range lines {
+			lines[i] = mdEscape(strings.TrimSpace(line))
+		}
+
+		fmt.Fprintf(sb, "| `%s` | %s | %s | %s | %s |\n",
+			optTitleWord(cfg.Name, cfg.IsNegatable),
+			strings.Join(aliases, ", "),
+			mdEscapeCell(arg),
+			mdEscapeCell(strings.Join(defaults, ", ")),
+			strings.Join(lines, "<br>"),
+		)
+	}
+}
+
+// MakeHTMLReference is a function which generates a reference document of a
+// command and its sub commands in HTML from a command configuration.
+// The content of the document is same as the one generated by
+// MakeMarkdownReference, and the document is an HTML fragment which can be
+// embedded in a page.
+func MakeHTMLReference(cmdCfg CmdCfg) string {
+	var sb strings.Builder
+
+	for i, node := range collectCompNodes(cmdCfg) {
+		tag := "h2"
+		if i == 0 {
+			tag = "h1"
+		}
+		fmt.Fprintf(&sb, "<section id=\"%s\">\n", refAnchor(node.path))
+		fmt.Fprintf(&sb, "<%s>%s</%s>\n", tag, html.EscapeString(node.path), tag)
+
+		for _, para := range splitParagraphs(node.cmdCfg.Desc) {
+			fmt.Fprintf(&sb, "<p>%s</p>\n", html.EscapeString(para))
+		}
+
+		writeHTMLOptTable(&sb, "Options", selectOptCfgs(node.optCfgs, false))
+		writeHTMLOptTable(&sb, "Global options", selectOptCfgs(node.optCfgs, true))
+
+		if len(node.cmdCfg.SubCmds) > 0 {
+			sb.WriteString("<h3>Sub commands</h3>\n")
+			sb.WriteString("<ul>\n")
+			for _, sub := range node.cmdCfg.SubCmds {
+				subPath := node.path + " " + sub.Name
+				fmt.Fprintf(&sb, "<li><a href=\"#%s\"><code>%s</code></a>",
+					refAnchor(subPath), html.EscapeString(subPath))
+				desc := firstLine(sub.Desc)
+				if len(desc) > 0 {
+					sb.WriteString(" - " + html.EscapeString(desc))
+				}
+				sb.WriteString("</li>\n")
+			}
+			sb.WriteString("</ul>\n")
+		}
+
+		sb.WriteString("</section>\n")
+	}
+
+	return sb.String()
+}
+
+func writeHTMLOptTable(sb *strings.Builder, title string, optCfgs []OptCfg) {
+	if len(optCfgs) == 0 {
+		return
+	}
+
+	fmt.Fprintf(sb, "<h3>%s</h3>\n", title)
+	sb.WriteString("<table>\n")
+	sb.WriteString("<thead><tr><th>Option</th><th>Aliases</th><th>Argument</th>" +
+		"<th>Default</th><th>Description</th></tr></thead>\n")
+	sb.WriteString("<tbody>\n")
+
+	for _, cfg := range optCfgs {
+		aliases := make([]string, len(cfg.Aliases))
+		for i, a := range cfg.Aliases {
+			aliases[i] = "<code>" + html.EscapeString(optTitleWord(a, cfg.IsNegatable)) + "</code>"
+		}
+
+		var arg string
+		if cfg.HasArg && len(optArgHelp(cfg)) > 0 {
+			arg = "<code>" + html.EscapeString(refArgHelp(cfg)) + "</code>"
+		}
+
+		defaults := make([]string, len(cfg.Default))
+		for i, d := range cfg.Default {
+			defaults[i] = "<code>" + html.EscapeString(d) + "</code>"
+		}
+
+		lines := strings.Split(strings.TrimSpace(makeOptDesc(cfg)), "\n")
+		for i, line := range lines {
+			lines[i] = html.EscapeString(strings.TrimSpace(line))
+		}
+
+		fmt.Fprintf(sb, "<tr><td><code>%s</code></td><td>%s</td><td>%s</td>"+
+			"<td>%s</td><td>%s</td></tr>\n",
+			html.EscapeString(optTitleWord(cfg.Name, cfg.IsNegatable)),
+			strings.Join(aliases, ", "),
+			arg,
+			strings.Join(defaults, ", "),
+			strings.Join(lines, "<br>"),
+		)
+	}
+
+	sb.WriteString("</tbody>\n")
+	sb.WriteString("</table>\n")
+}
+
+func refArgHelp(cfg OptCfg) string {
+	if cfg.IsArgOptional {
+		return "[=" + optArgHelp(cfg) + "]"
+	}
+	return optArgHelp(cfg)
+}
+
+func splitParagraphs(text string) []string {
+	paras := make([]string, 0)
+	lines := make([]string, 0)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			if len(lines) > 0 {
+				paras = append(paras, strings.Join(lines, "\n"))
+				lines = lines[:0]
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) > 0 {
+		paras = append(paras, strings.Join(lines, "\n"))
+	}
+	return paras
+}
+
+func refAnchor(path string) string {
+	return strings.ReplaceAll(strings.ToLower(path), " ", "-")
+}
+
+var mdReplacer = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`,
+)
+
+func mdEscape(s string) string {
+	return mdReplacer.Replace(s)
+}
+
+func mdEscapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
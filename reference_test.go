@@ -0,0 +1,199 @@
+package cliargs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sttk/cliargs"
+)
+
+func TestMakeMarkdownReference(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "my-app",
+		Desc: "My app does something.\nIt is useful.\n\nSecond paragraph.",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:     "verbose",
+				Aliases:  []string{"v"},
+				Desc:     "Shows details.",
+				IsGlobal: true,
+			},
+			cliargs.OptCfg{
+				Name:    "config",
+				HasArg:  true,
+				ArgHelp: "<file>",
+				Default: []string{"app.yml"},
+				Desc:    "Config file.\nIn YAML | JSON.",
+			},
+			cliargs.OptCfg{Name: "*"},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name: "remote",
+				Desc: "Manages remotes.\nMore about remotes.",
+				OptCfgs: []cliargs.OptCfg{
+					cliargs.OptCfg{
+						Name:    "f",
+						HasArg:  true,
+						IsArray: true,
+						Default: []string{"a", "b"},
+					},
+				},
+			},
+			cliargs.CmdCfg{Name: "list"},
+		},
+	}
+
+	doc := cliargs.MakeMarkdownReference(cmdCfg)
+	assert.Equal(t, doc, "# my-app\n"+
+		"\n"+
+		"My app does something.\n"+
+		"It is useful.\n"+
+		"\n"+
+		"Second paragraph.\n"+
+		"\n"+
+		"### Options\n"+
+		"\n"+
+		"| Option | Aliases | Argument | Default | Description |\n"+
+		"| --- | --- | --- | --- | --- |\n"+
+		"| `--config` |  | `<file>` | `app.yml` | Config file.<br>In YAML \\| JSON. |\n"+
+		"\n"+
+		"### Global options\n"+
+		"\n"+
+		"| Option | Aliases | Argument | Default | Description |\n"+
+		"| --- | --- | --- | --- | --- |\n"+
+		"| `--verbose` | `-v` |  |  | Shows details. |\n"+
+		"\n"+
+		"### Sub commands\n"+
+		"\n"+
+		"- [`my-app remote`](#my-app-remote) - Manages remotes.\n"+
+		"- [`my-app list`](#my-app-list)\n"+
+		"\n"+
+		"## my-app remote\n"+
+		"\n"+
+		"Manages remotes.\n"+
+		"More about remotes.\n"+
+		"\n"+
+		"### Options\n"+
+		"\n"+
+		"| Option | Aliases | Argument | Default | Description |\n"+
+		"| --- | --- | --- | --- | --- |\n"+
+		"| `-f` |  |  | `a`, `b` |  |\n"+
+		"\n"+
+		"### Global options\n"+
+		"\n"+
+		"| Option | Aliases | Argument | Default | Description |\n"+
+		"| --- | --- | --- | --- | --- |\n"+
+		"| `--verbose` | `-v` |  |  | Shows details. |\n"+
+		"\n"+
+		"## my-app list\n"+
+		"\n"+
+		"### Global options\n"+
+		"\n"+
+		"| Option | Aliases | Argument | Default | Description |\n"+
+		"| --- | --- | --- | --- | --- |\n"+
+		"| `--verbose` | `-v` |  |  | Shows details. |\n")
+}
+
+func TestMakeMarkdownReference_escape(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		Desc: "Use *stars* and <tags>.",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "a", HasArg: true, ArgHelp: "<x|y>"},
+		},
+	}
+	doc := cliargs.MakeMarkdownReference(cmdCfg)
+	assert.True(t, strings.Contains(doc, "\nUse \\*stars\\* and \\<tags\\>.\n"))
+	assert.True(t, strings.Contains(doc, "| `-a` |  | `<x\\|y>` |  |  |\n"))
+}
+
+func TestMakeMarkdownReference_requiredOpt(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "app",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name: "out", HasArg: true, IsRequired: true, Desc: "Output.",
+			},
+		},
+	}
+	doc := cliargs.MakeMarkdownReference(cmdCfg)
+	assert.True(t, strings.Contains(doc, "| `--out` |  |  |  | Output. (required) |\n"))
+}
+
+func TestMakeHTMLReference(t *testing.T) {
+	cmdCfg := cliargs.CmdCfg{
+		Name: "my-app",
+		Desc: "My app does something.\nIt is useful.\n\nSecond paragraph.",
+		OptCfgs: []cliargs.OptCfg{
+			cliargs.OptCfg{
+				Name:     "verbose",
+				Aliases:  []string{"v"},
+				Desc:     "Shows details.",
+				IsGlobal: true,
+			},
+			cliargs.OptCfg{
+				Name:    "config",
+				HasArg:  true,
+				ArgHelp: "<file>",
+				Default: []string{"app.yml"},
+				Desc:    "Config file.\nIn YAML | JSON.",
+			},
+			cliargs.OptCfg{Name: "*"},
+		},
+		SubCmds: []cliargs.CmdCfg{
+			cliargs.CmdCfg{
+				Name: "remote",
+				Desc: "Manages remotes.\nMore about remotes.",
+				OptCfgs: []cliargs.OptCfg{
+					cliargs.OptCfg{
+						Name:    "f",
+						HasArg:  true,
+						IsArray: true,
+						Default: []string{"a", "b"},
+					},
+				},
+			},
+			cliargs.CmdCfg{Name: "list"},
+		},
+	}
+
+	doc := cliargs.MakeHTMLReference(cmdCfg)
+
+	assert.True(t, strings.HasPrefix(doc, `<section id="my-app">
+<h1>my-app</h1>
+<p>My app does something.
+It is useful.</p>
+<p>Second paragraph.</p>
+<h3>Options</h3>
+<table>
+<thead><tr><th>Option</th><th>Aliases</th><th>Argument</th><th>Default</th><th>Description</th></tr></thead>
+<tbody>
+<tr><td><code>--config</code></td><td></td><td><code>&lt;file&gt;</code></td><td><code>app.yml</code></td><td>Config file.<br>In YAML | JSON.</td></tr>
+</tbody>
+</table>
+`))
+	assert.True(t, strings.Contains(doc, `<h3>Sub commands</h3>
+<ul>
+<li><a href="#my-app-remote"><code>my-app remote</code></a> - Manages remotes.</li>
+<li><a href="#my-app-list"><code>my-app list</code></a></li>
+</ul>
+</section>
+<section id="my-app-remote">
+<h2>my-app remote</h2>
+`))
+	assert.True(t, strings.Contains(doc, `<tr><td><code>-f</code></td><td></td><td></td><td><code>a</code>, <code>b</code></td><td></td></tr>`))
+	assert.True(t, strings.HasSuffix(doc, `<section id="my-app-list">
+<h2>my-app list</h2>
+<h3>Global options</h3>
+<table>
+<thead><tr><th>Option</th><th>Aliases</th><th>Argument</th><th>Default</th><th>Description</th></tr></thead>
+<tbody>
+<tr><td><code>--verbose</code></td><td><code>-v</code></td><td></td><td></td><td>Shows details.</td></tr>
+</tbody>
+</table>
+</section>
+`))
+}
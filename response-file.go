@@ -0,0 +1,261 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ResponseFileError is an error which indicates that a response file given
+// like @file cannot be expanded.
+// File is the path of the response file, or "-" for standard input, and Line
+// is the line number in the file where the error occurred.
+// If a response file given in command line arguments cannot be read, File is
+// the path of the file and Line is zero, and if a response file included by
+// another response file cannot be read, File and Line indicate where it is
+// included.
+// Err is the error which indicates the cause, and can be taken with
+// errors.Unwrap, errors.Is, or errors.As.
+type ResponseFileError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e ResponseFileError) Error() string {
+	return fmt.Sprintf("ResponseFileError{File:%s,Line:%d,Err:%s}",
+		e.File, e.Line, e.Err.Error())
+}
+
+// Unwrap is a method which returns the error which indicates the cause.
+func (e ResponseFileError) Unwrap() error {
+	return e.Err
+}
+
+// ResponseFileIsCyclic is an error which indicates that a response file
+// includes itself directly or indirectly.
+type ResponseFileIsCyclic struct{ File string }
+
+func (e ResponseFileIsCyclic) Error() string {
+	return fmt.Sprintf("ResponseFileIsCyclic{File:%s}", e.File)
+}
+
+// ResponseFileHasUnclosedQuote is an error which indicates that a quotation in
+// a response file is not closed.
+type ResponseFileHasUnclosedQuote struct{ Quote string }
+
+func (e ResponseFileHasUnclosedQuote) Error() string {
+	return fmt.Sprintf("ResponseFileHasUnclosedQuote{Quote:%s}", e.Quote)
+}
+
+const stdinFile = "-"
+
+// rfToken is a structure which holds a token in a response file and the line
+// number where the token starts.
+type rfToken struct {
+	text string
+	line int
+}
+
+// responseFiles is a structure which holds the state of expanding response
+// files: the stack of the response files being expanded for cycle detection,
+// and a flag which indicates that "--" has been found.
+type responseFiles struct {
+	stack    []string
+	isNonOpt bool
+}
+
+// expandResponseFiles is a function which replaces the command line arguments
+// starting with "@" with the tokens in the response files.
+// The command line arguments after "--" are not expanded.
+func expandResponseFiles(osArgs []string) ([]string, error) {
+	rf := responseFiles{}
+	expanded := make([]string, 0, len(osArgs))
+
+	for _, arg := range osArgs {
+		var err error
+		expanded, err = rf.expand(expanded, arg, "", 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return expanded, nil
+}
+
+func (rf *responseFiles) expand(
+	expanded []string, arg string, parent string, line int,
+) ([]string, error) {
+	if rf.isNonOpt || len(arg) < 2 || arg[0] != '@' {
+		if arg == "--" {
+			rf.isNonOpt = true
+		}
+		return append(expanded, arg), nil
+	}
+
+	file := arg[1:]
+	key := file
+	if file != stdinFile {
+		if abs, err := filepath.Abs(file); err == nil {
+			key = abs
+		}
+	}
+	for _, f := range rf.stack {
+		if f == key {
+			return nil, ResponseFileError{
+				File: parent, Line: line, Err: ResponseFileIsCyclic{File: file}}
+		}
+	}
+
+	data, err := readResponseFile(file)
+	if err != nil {
+		if len(parent) > 0 {
+			return nil, ResponseFileError{File: parent, Line: line, Err: err}
+		}
+		return nil, ResponseFileError{File: file, Err: err}
+	}
+
+	tokens, err := splitResponseFile(file, data)
+	if err != nil {
+		return nil, err
+	}
+
+	rf.stack = append(rf.stack, key)
+	for _, tok := range tokens {
+		expanded, err = rf.expand(expanded, tok.text, file, tok.line)
+		if err != nil {
+			return nil, err
+		}
+	}
+	rf.stack = rf.stack[0 : len(rf.stack)-1]
+
+	return expanded, nil
+}
+
+func readResponseFile(file string) (string, error) {
+	if file == stdinFile {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", &os.PathError{Op: "read", Path: file, Err: err}
+		}
+		return string(data), nil
+	}
+	data, err := os.ReadFile(file)
+	return string(data), err
+}
+
+// splitResponseFile is a function which splits the content of a response file
+// into tokens in the similar way to a shell.
+// Tokens are separated by white spaces and line breaks, and a token can be
+// quoted with single quotes or double quotes to include white spaces.
+// In a double-quoted string, a backslash escapes only ", \, $, and `, and
+// outside quotes, a backslash escapes any character.
+// A backslash followed by a line break joins lines.
+// A token starting with # and the rest of its line are a comment.
+func splitResponseFile(file, data string) ([]rfToken, error) {
+	tokens := make([]rfToken, 0)
+
+	var buf strings.Builder
+	inToken := false
+	tokenLine := 0
+	quote := rune(0)
+	quoteLine := 0
+	isEscaped := false
+	isComment := false
+	line := 1
+
+	var startToken = func() {
+		if !inToken {
+			inToken = true
+			tokenLine = line
+		}
+	}
+
+	for _, r := range data {
+		if isComment {
+			if r == '\n' {
+				isComment = false
+				line++
+			}
+			continue
+		}
+
+		if isEscaped {
+			isEscaped = false
+			if r == '\n' {
+				line++
+				continue
+			}
+			startToken()
+			if quote == '"' && !strings.ContainsRune("\"\\$`", r) {
+				buf.WriteRune('\\')
+			}
+			buf.WriteRune(r)
+			continue
+		}
+
+		switch quote {
+		case '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				buf.WriteRune(r)
+			}
+		case '"':
+			if r == '"' {
+				quote = 0
+			} else if r == '\\' {
+				isEscaped = true
+			} else {
+				buf.WriteRune(r)
+			}
+		default:
+			switch r {
+			case ' ', '\t', '\r', '\n':
+				if inToken {
+					tokens = append(tokens, rfToken{text: buf.String(), line: tokenLine})
+					buf.Reset()
+					inToken = false
+				}
+			case '\'', '"':
+				startToken()
+				quote = r
+				quoteLine = line
+			case '\\':
+				isEscaped = true
+			case '#':
+				if inToken {
+					buf.WriteRune(r)
+				} else {
+					isComment = true
+				}
+			default:
+				startToken()
+				buf.WriteRune(r)
+			}
+		}
+
+		if r == '\n' {
+			line++
+		}
+	}
+
+	if quote != 0 {
+		return nil, ResponseFileError{File: file, Line: quoteLine,
+			Err: ResponseFileHasUnclosedQuote{Quote: string(quote)}}
+	}
+	if isEscaped {
+		startToken()
+		buf.WriteRune('\\')
+	}
+	if inToken {
+		tokens = append(tokens, rfToken{text: buf.String(), line: tokenLine})
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,188 @@
+package cliargs_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sttk/cliargs"
+)
+
+func writeResponseFile(t *testing.T, dir, name, content string) string {
+	file := filepath.Join(dir, name)
+	err := os.WriteFile(file, []byte(content), 0644)
+	assert.Nil(t, err)
+	return file
+}
+
+func TestParseWith_responseFiles(t *testing.T) {
+	dir := t.TempDir()
+	file := writeResponseFile(t, dir, "args.txt", `# options
+--define 'a b'
+--define "c \"d\" \e"
+  -v  x\ y \
+z
+""
+`)
+
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "define", HasArg: true, IsArray: true},
+		cliargs.OptCfg{Name: "v"},
+	}
+
+	osArgs := []string{"app", "@" + file, "w", "--", "@" + file}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithResponseFiles())
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArgs("define"), []string{"a b", `c "d" \e`})
+	assert.True(t, cmd.HasOpt("v"))
+	assert.Equal(t, cmd.Args(), []string{"x y", "z", "", "w", "@" + file})
+
+	cmd, err = cliargs.ParseWith(osArgs, optCfgs)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.Args(), []string{"@" + file, "w", "@" + file})
+}
+
+func TestParseWith_responseFiles_backslash(t *testing.T) {
+	dir := t.TempDir()
+	file := writeResponseFile(t, dir, "args.txt", `'C:\dir\a.txt' C:\dir\b.txt`)
+
+	osArgs := []string{"app", "@" + file}
+	cmd, err := cliargs.ParseWith(osArgs, nil, cliargs.WithResponseFiles())
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.Args(), []string{`C:\dir\a.txt`, `C:dirb.txt`})
+}
+
+func TestParseWith_responseFiles_nested(t *testing.T) {
+	dir := t.TempDir()
+	inner := writeResponseFile(t, dir, "inner.txt", "-v\nb\n")
+	outer := writeResponseFile(t, dir, "outer.txt", "a\n'@"+inner+"'\nc\n")
+
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "v"},
+	}
+
+	osArgs := []string{"app", "@" + outer, "@" + inner}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithResponseFiles())
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("v"))
+	assert.Equal(t, cmd.Args(), []string{"a", "b", "c", "b"})
+}
+
+func TestParseWith_responseFiles_cyclic(t *testing.T) {
+	dir := t.TempDir()
+	file1 := filepath.Join(dir, "1.txt")
+	file2 := writeResponseFile(t, dir, "2.txt", "a\n\n'@"+file1+"'\n")
+	writeResponseFile(t, dir, "1.txt", "-v '@"+file2+"'\n")
+
+	osArgs := []string{"app", "@" + file1}
+	_, err := cliargs.ParseWith(osArgs, nil, cliargs.WithResponseFiles())
+	assert.Equal(t, err.Error(), "ResponseFileError{File:"+file2+
+		",Line:3,Err:ResponseFileIsCyclic{File:"+file1+"}}")
+	var e cliargs.ResponseFileIsCyclic
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, e.File, file1)
+}
+
+func TestParseWith_responseFiles_unclosedQuote(t *testing.T) {
+	dir := t.TempDir()
+	file := writeResponseFile(t, dir, "args.txt", "a\nb 'c\nd\n")
+
+	osArgs := []string{"app", "@" + file}
+	_, err := cliargs.ParseWith(osArgs, nil, cliargs.WithResponseFiles())
+	assert.Equal(t, err.Error(), "ResponseFileError{File:"+file+
+		",Line:2,Err:ResponseFileHasUnclosedQuote{Quote:'}}")
+	switch e := err.(type) {
+	case cliargs.ResponseFileError:
+		assert.Equal(t, e.File, file)
+		assert.Equal(t, e.Line, 2)
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseWith_responseFiles_notFound(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "none.txt")
+
+	osArgs := []string{"app", "@" + file}
+	cmd, err := cliargs.ParseWith(osArgs, nil, cliargs.WithResponseFiles())
+	var e cliargs.ResponseFileError
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, e.File, file)
+	assert.Equal(t, e.Line, 0)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	assert.Equal(t, cmd.Args(), []string{})
+}
+
+func TestParseWith_responseFiles_nestedNotFound(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing.txt")
+	file := writeResponseFile(t, dir, "args.txt", "a\n\n'@"+missing+"'\n")
+
+	osArgs := []string{"app", "@" + file}
+	_, err := cliargs.ParseWith(osArgs, nil, cliargs.WithResponseFiles())
+	var e cliargs.ResponseFileError
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, e.File, file)
+	assert.Equal(t, e.Line, 3)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	var pe *os.PathError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, pe.Path, missing)
+}
+
+func TestParseWith_responseFiles_stdin(t *testing.T) {
+	dir := t.TempDir()
+	file := writeResponseFile(t, dir, "stdin.txt", "-v a\n")
+
+	stdin := os.Stdin
+	defer func() { os.Stdin = stdin }()
+	f, err := os.Open(file)
+	assert.Nil(t, err)
+	defer f.Close()
+	os.Stdin = f
+
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "v"},
+	}
+
+	osArgs := []string{"app", "@-", "@"}
+	cmd, err := cliargs.ParseWith(osArgs, optCfgs, cliargs.WithResponseFiles())
+	assert.Nil(t, err)
+	assert.True(t, cmd.HasOpt("v"))
+	assert.Equal(t, cmd.Args(), []string{"a", "@"})
+}
+
+func TestParse_responseFiles(t *testing.T) {
+	defer resetOsArgs()
+
+	dir := t.TempDir()
+	file := writeResponseFile(t, dir, "args.txt", "--foo=1 bar\n")
+
+	os.Args = []string{"app", "@" + file}
+	cmd, err := cliargs.Parse(cliargs.WithResponseFiles())
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.OptArg("foo"), "1")
+	assert.Equal(t, cmd.Args(), []string{"bar"})
+
+	cmd, err = cliargs.Parse()
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.Args(), []string{"@" + file})
+}
+
+func TestParseFor_responseFiles(t *testing.T) {
+	type MyOptions struct {
+		Names []string `optcfg:"name,n"`
+	}
+
+	dir := t.TempDir()
+	file := writeResponseFile(t, dir, "args.txt", "-n a\n-n 'b c'\n")
+
+	options := MyOptions{}
+	osArgs := []string{"app", "@" + file, "-n", "d"}
+	_, _, err := cliargs.ParseFor(osArgs, &options, cliargs.WithResponseFiles())
+	assert.Nil(t, err)
+	assert.Equal(t, options.Names, []string{"a", "b c", "d"})
+}